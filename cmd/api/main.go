@@ -1,42 +1,251 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/PicPay/go-test-workshop/internal/api"
+	"github.com/PicPay/go-test-workshop/internal/config"
 	"github.com/PicPay/go-test-workshop/internal/http/echo"
+	"github.com/PicPay/go-test-workshop/internal/logging"
+	"github.com/PicPay/go-test-workshop/internal/metrics"
+	"github.com/PicPay/go-test-workshop/internal/migrations"
+	"github.com/PicPay/go-test-workshop/internal/tracing"
 	"github.com/PicPay/go-test-workshop/person"
+	"github.com/PicPay/go-test-workshop/person/inmem"
 	"github.com/PicPay/go-test-workshop/person/mysql"
+	"github.com/PicPay/go-test-workshop/person/postgres"
+	"github.com/PicPay/go-test-workshop/person/sqlite"
 	"github.com/PicPay/go-test-workshop/weather"
 	logger "github.com/PicPay/lib-go-logger"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
-const ( //@todo pegar essa informação de variáveis de ambiente
-	dbUser         = "workshop"
-	dbPassword     = "workshop"
-	database       = "workshop"
-	dbRootPassword = "db-root-password"
-)
-
+//Uso:
+//
+//	api                      inicia a API, aplicando antes as migrações pendentes se migrate_on_start estiver habilitado
+//	api migrate up|down|version
+//
+//A configuração é lida do arquivo indicado em CONFIG_FILE (opcional) e das variáveis de ambiente. Veja o pacote internal/config
 func main() {
-	dbUri := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", dbUser, dbPassword, "localhost", "3306", database)
-	db, err := sql.Open("mysql", dbUri)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(os.Args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := openDB(cfg.DB)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.MigrateOnStart {
+		err = migrate(db, cfg.DB.Driver, []string{"up"})
+		if err != nil {
+			log.Fatal(err)
+		}
+		if cfg.SeedFile != "" {
+			err = seed(db, cfg.DB.Driver, cfg.SeedFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	repo, err := repository(cfg.DB.Driver, db)
 	if err != nil {
 		log.Fatal(err)
 	}
-	repo := mysql.NewMySQL(db)
-	pService := person.NewService(repo)
+	tp, err := tracing.NewProvider(context.Background(), cfg.Tracing.Exporter, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tr := tracing.New(tp)
+	//os componentes são encerrados na ordem inversa: o servidor HTTP, adicionado pelo api.Start, depois o pool
+	//de conexões do banco de dados e por último o envio dos spans pendentes
+	lc := api.NewLifecycle()
+	lc.Append(api.Hook{Name: "tracing", Stop: tp.Shutdown})
+	if db != nil {
+		lc.Append(api.Hook{
+			Name: "db",
+			Stop: func(context.Context) error { return db.Close() },
+		})
+	}
+	m := metrics.New()
+	if db != nil {
+		m.RegisterDB(db, cfg.DB.Name)
+	}
+	pService := tr.UseCase(person.NewService(tr.Repository(m.Repository(repo), cfg.DB.Driver)))
 
-	wService := weather.NewService(os.Getenv("API_KEY"))
+	var providers []weather.NamedProvider
+	//o estado dos provedores aparece no /healthz, mas não no /readyz: uma falha da previsão do tempo
+	//não deve tirar as réplicas do balanceador e derrubar também as rotas de /people
+	var details []api.Option
+	for _, name := range cfg.Weather.Providers() {
+		provider, check := weatherProvider(name, cfg.Weather, m, tr)
+		providers = append(providers, weather.NamedProvider{Name: name, UseCase: provider})
+		if check != nil {
+			details = append(details, api.WithDetail("weather."+name, check))
+		}
+	}
+	var wService weather.UseCase = providers[0].UseCase
+	if len(providers) > 1 {
+		wService = weather.NewFallback(providers[0], providers[1:]...)
+	}
+	if cfg.Weather.CacheTTL > 0 {
+		cache := weather.NewCache(wService,
+			weather.WithTTL(time.Duration(cfg.Weather.CacheTTL)),
+			weather.WithMaxEntries(cfg.Weather.CacheSize),
+			weather.WithCacheDefaults(weather.Options{Units: weather.Units(cfg.Weather.Units), Lang: cfg.Weather.Lang}),
+		)
+		m.RegisterCache(cache)
+		wService = cache
+	}
 
 	l := logger.New()
-	h := echo.Handlers(l, pService, wService)
-	err = api.Start(l, "8000", h)
+	h := echo.Handlers(logging.New(os.Stdout), pService, wService)
+	h.Use(tr.Echo(), m.Echo())
+	options := []api.Option{
+		api.WithLifecycle(lc),
+		api.WithMetrics(m.Handler()),
+		api.WithShutdownDelay(time.Duration(cfg.ShutdownDelay)),
+	}
+	options = append(options, details...)
+	if db != nil {
+		options = append(options, api.WithCheck("db", db.PingContext))
+	}
+	err = api.Start(l, cfg.Port, h, options...)
 	if err != nil {
 		l.Fatal("error running api", err)
 	}
 }
+
+//weatherProvider cria o provedor de previsão do tempo e a verificação informada no /healthz. Cada provedor HTTP tem o seu
+//próprio circuit breaker, que fica por fora das novas tentativas: uma chamada que falhou em todas as tentativas conta
+//como uma falha. A verificação apenas lê o estado do circuito, sem chamar o provedor, e falha enquanto ele estiver aberto
+func weatherProvider(name string, cfg config.Weather, m *metrics.Metrics, tr *tracing.Tracing) (weather.UseCase, api.Check) {
+	breaker := weather.NewCircuitBreaker(
+		weather.NewRetryClient(tr.HTTPClient(&http.Client{Timeout: time.Duration(cfg.Timeout)}),
+			weather.WithMaxRetries(cfg.MaxRetries),
+		),
+	)
+	client := m.HTTPClient(breaker, name)
+	defaults := weather.Options{
+		Units: weather.Units(cfg.Units),
+		Lang:  cfg.Lang,
+	}
+	switch name {
+	case "openmeteo":
+		return weather.NewOpenMeteo(
+			weather.WithOpenMeteoClient(client),
+			weather.WithOpenMeteoDefaults(defaults),
+		), breaker.Check
+	case "static":
+		return weather.NewStatic(), nil
+	default:
+		options := []weather.ServiceOption{
+			weather.WithClient(client),
+			weather.WithUnits(defaults.Units),
+			weather.WithLang(defaults.Lang),
+		}
+		if cfg.BaseURL != "" {
+			options = append(options, weather.WithBaseURL(cfg.BaseURL))
+		}
+		return weather.NewService(cfg.APIKey, options...), breaker.Check
+	}
+}
+
+//openDB abre a conexão com o banco de dados configurado. O driver inmem não usa banco de dados e retorna nil
+func openDB(cfg config.DB) (*sql.DB, error) {
+	if cfg.Driver == "inmem" {
+		return nil, nil
+	}
+	return sql.Open(cfg.SQLDriver(), cfg.DSN())
+}
+
+//repository cria o person.Repository de acordo com o driver. Use o driver inmem para executar a API sem banco de dados
+func repository(driver string, db *sql.DB) (person.Repository, error) {
+	switch driver {
+	case "mysql":
+		return mysql.NewMySQL(db), nil
+	case "postgres":
+		return postgres.NewPostgres(db), nil
+	case "sqlite":
+		return sqlite.NewSQLite(db), nil
+	case "inmem":
+		return inmem.NewInMem(), nil
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q", driver)
+	}
+}
+
+//runMigrate executa o comando migrate, que depende apenas da configuração do banco de dados
+func runMigrate(args []string) error {
+	cfg, err := config.LoadDB(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		return err
+	}
+	db, err := openDB(cfg.DB)
+	if err != nil {
+		return err
+	}
+	if db != nil {
+		defer db.Close()
+	}
+	return migrate(db, cfg.DB.Driver, args)
+}
+
+func migrate(db *sql.DB, driver string, args []string) error {
+	if db == nil {
+		return fmt.Errorf("DB_DRIVER %q does not support migrations", driver)
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s migrate up|down|version", os.Args[0])
+	}
+	ctx := context.Background()
+	m, err := migrations.New(db, migrations.Dialect(driver))
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "up":
+		err = m.Up(ctx)
+	case "down":
+		err = m.Down(ctx)
+	case "version":
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("schema version: %d\n", version)
+	return nil
+}
+
+//seed executa o script com os dados de exemplo, como os de ops/db/seed, que deve ser escrito no dialeto do driver
+func seed(db *sql.DB, driver, path string) error {
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading seed file: %w", err)
+	}
+	m, err := migrations.New(db, migrations.Dialect(driver))
+	if err != nil {
+		return err
+	}
+	return m.Seed(context.Background(), string(script))
+}
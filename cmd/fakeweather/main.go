@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/PicPay/go-test-workshop/weather/fake"
+)
+
+//Uso:
+//
+//	fakeweather [-addr :8081] [-api-key chave] [-latency 200ms]
+//
+//Inicia um servidor que simula a API da OpenWeather, para executar a API sem acesso à internet:
+//
+//	WEATHER_BASE_URL=http://localhost:8081/data/2.5 API_KEY=fake go run ./cmd/api
+//
+//Todas as coordenadas retornam as condições de weather.NewStatic. Consultas por cidade e código postal retornam 404
+func main() {
+	addr := flag.String("addr", ":8081", "endereço do servidor")
+	apiKey := flag.String("api-key", "", "chave aceita pelo servidor, vazio aceita qualquer chave")
+	latency := flag.Duration("latency", 0, "atraso de cada resposta")
+	flag.Parse()
+
+	options := []fake.Option{fake.WithLatency(*latency)}
+	if *apiKey != "" {
+		options = append(options, fake.WithAPIKey(*apiKey))
+	}
+	log.Printf("fake OpenWeather listening on %s%s", *addr, fake.BasePath)
+	log.Fatal(http.ListenAndServe(*addr, fake.New(options...)))
+}
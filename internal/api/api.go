@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os/signal"
 	"syscall"
@@ -13,48 +14,130 @@ import (
 
 const TIMEOUT = 30 * time.Second
 
+//Option configura a execução da API
+type Option func(*options)
+
+type options struct {
+	checks        map[string]Check
+	details       map[string]Check
+	shutdownDelay time.Duration
+	metrics       http.Handler
+	lifecycle     *Lifecycle
+}
+
+//WithCheck adiciona uma verificação ao /readyz, como o ping do banco de dados
+func WithCheck(name string, check Check) Option {
+	return func(o *options) {
+		o.checks[name] = check
+	}
+}
+
+//WithDetail adiciona ao /healthz o resultado de uma dependência que não impede o atendimento das outras rotas,
+//como os provedores de previsão do tempo. Uma falha aparece como degraded na resposta, que continua com status 200
+func WithDetail(name string, check Check) Option {
+	return func(o *options) {
+		o.details[name] = check
+	}
+}
+
+//WithMetrics serve as métricas em /metrics. As requisições ao /metrics, ao /healthz e ao /readyz não passam pelo
+//handler da API, então não são contadas nas métricas de HTTP
+func WithMetrics(h http.Handler) Option {
+	return func(o *options) {
+		o.metrics = h
+	}
+}
+
+//WithLifecycle inicia e encerra os componentes de lc junto com a API. O servidor HTTP é adicionado por último,
+//então é o primeiro a ser encerrado e os outros componentes, como o banco de dados, continuam disponíveis
+//até o fim das requisições em andamento
+func WithLifecycle(lc *Lifecycle) Option {
+	return func(o *options) {
+		o.lifecycle = lc
+	}
+}
+
+//WithShutdownDelay define quanto tempo a API continua atendendo depois que o /readyz passa a responder 503,
+//dando tempo para o balanceador de carga (como o Kubernetes) parar de enviar tráfego antes das conexões serem encerradas
+func WithShutdownDelay(d time.Duration) Option {
+	return func(o *options) {
+		o.shutdownDelay = d
+	}
+}
+
 //@todo esse pacote poderia ser uma lib compartilhada
-func Start(l *logger.Logger, port string, handler http.Handler) error {
+func Start(l *logger.Logger, port string, handler http.Handler, opts ...Option) error {
+	o := &options{checks: make(map[string]Check), details: make(map[string]Check)}
+	for _, opt := range opts {
+		opt(o)
+	}
+	lc := o.lifecycle
+	if lc == nil {
+		lc = NewLifecycle()
+	}
+	health := NewHealth(o.checks, o.details)
+	if o.metrics != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", o.metrics)
+		mux.Handle("/", handler)
+		handler = mux
+	}
 	srv := &http.Server{
 		ReadTimeout:  TIMEOUT,
 		WriteTimeout: TIMEOUT,
 		Addr:         ":" + port,
-		Handler:      handler,
+		Handler:      health.Handler(handler),
 	}
+	errServe := make(chan error, 1)
+	lc.Append(serverHook(srv, health, o.shutdownDelay, errServe))
 
 	ctx, stop := signal.NotifyContext(
 		context.Background(),
 		syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT,
 	)
 	defer stop()
-	errShutdown := make(chan error, 1)
-	go shutdown(srv, ctx, errShutdown)
-
-	l.Info(fmt.Sprintf("Current service listening on port %s\n", port))
-	err := srv.ListenAndServe()
-	if err != nil && err != http.ErrServerClosed {
+	if err := lc.Start(ctx); err != nil {
 		return err
 	}
-	err = <-errShutdown
-	if err != nil {
-		return err
+	l.Info(fmt.Sprintf("Current service listening on port %s\n", port))
+
+	var errs Errors
+	select {
+	case <-ctx.Done():
+		l.Info("Shutting down the service")
+	case err := <-errServe:
+		errs = append(errs, &HookError{Name: "http", Op: "serve", Err: err})
 	}
-	return nil
+	return errs.append(lc.Stop(context.Background())).err()
 }
 
-func shutdown(server *http.Server, ctxShutdown context.Context, errShutdown chan error) {
-	<-ctxShutdown.Done()
-
-	ctxTimeout, stop := context.WithTimeout(context.Background(), TIMEOUT)
-	defer stop()
-
-	err := server.Shutdown(ctxTimeout)
-	switch err {
-	case nil:
-		errShutdown <- nil
-	case context.DeadlineExceeded:
-		errShutdown <- fmt.Errorf("Forcing closing the server")
-	default:
-		errShutdown <- fmt.Errorf("Forcing closing the server")
+//serverHook abre a porta ao iniciar, para que um erro como porta em uso seja retornado por Lifecycle.Start, e atende
+//as requisições em uma goroutine, enviando para errServe o erro se o servidor parar antes do encerramento.
+//Ao encerrar, marca a API como não pronta e, depois de delay, fecha o servidor esperando as requisições em andamento
+func serverHook(server *http.Server, health *Health, delay time.Duration, errServe chan<- error) Hook {
+	return Hook{
+		Name: "http",
+		Start: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := server.Serve(ln); err != http.ErrServerClosed {
+					errServe <- err
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			health.Shutdown()
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return server.Shutdown(ctx)
+		},
+		Timeout: delay + TIMEOUT,
 	}
 }
@@ -16,6 +16,7 @@ func TestStart(t *testing.T) {
 	t.Run("retorna erro ao executar ListenAndServe com porta inválida", func(t *testing.T) {
 		logger := logger.New()
 		err := Start(logger, "abacate", mockHandler{})
-		assert.Contains(t, err.Error(), "listen tcp: lookup tcp/abacate: nodename nor servname provided, or not known")
+		//a mensagem da resolução da porta muda com o sistema operacional, então só o início é verificado
+		assert.ErrorContains(t, err, "listen tcp")
 	})
 }
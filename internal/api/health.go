@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//CheckTimeout tempo máximo de cada verificação do /readyz e do /healthz
+const CheckTimeout = 2 * time.Second
+
+//Check verifica se uma dependência da API, como o banco de dados, está disponível
+type Check func(ctx context.Context) error
+
+//Health responde às sondas de liveness (/healthz) e readiness (/readyz)
+type Health struct {
+	checks   map[string]Check
+	details  map[string]Check
+	shutdown int32
+}
+
+//healthResponse é o corpo das respostas de /healthz e /readyz. Checks tem "ok" ou o erro de cada verificação
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+//NewHealth cria as sondas com as verificações do /readyz e as informadas no /healthz, indexadas pelo nome da dependência.
+//Apenas as dependências sem as quais nenhuma requisição pode ser atendida, como o banco de dados, devem estar em checks:
+//se uma delas falhar todas as réplicas deixam de receber tráfego. As demais, como os provedores de previsão do tempo,
+//ficam em details
+func NewHealth(checks, details map[string]Check) *Health {
+	return &Health{checks: checks, details: details}
+}
+
+//Shutdown faz o /readyz responder 503, para que o tráfego deixe de ser enviado enquanto as conexões são encerradas
+func (h *Health) Shutdown() {
+	atomic.StoreInt32(&h.shutdown, 1)
+}
+
+//Handler atende /healthz e /readyz e repassa as outras requisições para next
+func (h *Health) Handler(next http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", h.Live)
+	mux.HandleFunc("/readyz", h.Ready)
+	mux.Handle("/", next)
+	return mux
+}
+
+//Live responde 200 enquanto o processo está executando. O resultado das verificações de details é incluído na
+//resposta, com o status degraded quando alguma falha, mas não altera o status HTTP
+func (h *Health) Live(w http.ResponseWriter, r *http.Request) {
+	if len(h.details) == 0 {
+		writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+		return
+	}
+	resp := healthResponse{Status: "ok"}
+	var ok bool
+	resp.Checks, ok = run(r.Context(), h.details)
+	if !ok {
+		resp.Status = "degraded"
+	}
+	writeHealth(w, http.StatusOK, resp)
+}
+
+//Ready executa as verificações em paralelo e responde 200 se todas passarem ou 503 caso alguma falhe
+//ou a API esteja sendo encerrada
+func (h *Health) Ready(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&h.shutdown) == 1 {
+		writeHealth(w, http.StatusServiceUnavailable, healthResponse{Status: "shutting_down"})
+		return
+	}
+	resp := healthResponse{Status: "ok"}
+	status := http.StatusOK
+	var ok bool
+	resp.Checks, ok = run(r.Context(), h.checks)
+	if !ok {
+		resp.Status = "unavailable"
+		status = http.StatusServiceUnavailable
+	}
+	writeHealth(w, status, resp)
+}
+
+//run executa as verificações em paralelo e retorna "ok" ou o erro de cada uma, e se todas passaram
+func run(ctx context.Context, checks map[string]Check) (map[string]string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+	results := make(map[string]string, len(checks))
+	ok := true
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := "ok"
+			err := check(ctx)
+			if err != nil {
+				result = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = result
+			if err != nil {
+				ok = false
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return results, ok
+}
+
+func writeHealth(w http.ResponseWriter, status int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error {
+		return errors.New("dial tcp 127.0.0.1:3306: connect: connection refused")
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	get := func(h http.Handler, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+	t.Run("liveness", func(t *testing.T) {
+		h := NewHealth(map[string]Check{"db": down}, nil).Handler(next)
+		rec := get(h, "/healthz")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+	})
+	t.Run("liveness informa as dependências degradadas", func(t *testing.T) {
+		h := NewHealth(map[string]Check{"db": ok}, map[string]Check{"weather.openweather": down, "weather.openmeteo": ok}).Handler(next)
+		rec := get(h, "/healthz")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"degraded","checks":{"weather.openweather":"dial tcp 127.0.0.1:3306: connect: connection refused","weather.openmeteo":"ok"}}`, rec.Body.String())
+	})
+	t.Run("dependências degradadas não afetam a readiness", func(t *testing.T) {
+		h := NewHealth(map[string]Check{"db": ok}, map[string]Check{"weather.openweather": down}).Handler(next)
+		rec := get(h, "/readyz")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"ok","checks":{"db":"ok"}}`, rec.Body.String())
+	})
+	t.Run("pronta quando todas as verificações passam", func(t *testing.T) {
+		h := NewHealth(map[string]Check{"db": ok, "cache": ok}, nil).Handler(next)
+		rec := get(h, "/readyz")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"ok","checks":{"db":"ok","cache":"ok"}}`, rec.Body.String())
+	})
+	t.Run("não pronta quando alguma verificação falha", func(t *testing.T) {
+		h := NewHealth(map[string]Check{"db": down, "cache": ok}, nil).Handler(next)
+		rec := get(h, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.JSONEq(t, `{"status":"unavailable","checks":{"db":"dial tcp 127.0.0.1:3306: connect: connection refused","cache":"ok"}}`, rec.Body.String())
+	})
+	t.Run("não pronta durante o encerramento", func(t *testing.T) {
+		health := NewHealth(map[string]Check{"db": ok}, nil)
+		health.Shutdown()
+		h := health.Handler(next)
+		rec := get(h, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.JSONEq(t, `{"status":"shutting_down"}`, rec.Body.String())
+		assert.Equal(t, http.StatusOK, get(h, "/healthz").Code)
+	})
+	t.Run("repassa as outras rotas", func(t *testing.T) {
+		h := NewHealth(nil, nil).Handler(next)
+		assert.Equal(t, http.StatusTeapot, get(h, "/weather/-27.5969/-48.5495").Code)
+	})
+}
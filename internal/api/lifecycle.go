@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+//ErrHookTimeout é retornado quando um componente não termina o início ou o encerramento dentro do prazo
+var ErrHookTimeout = errors.New("hook timed out")
+
+//Hook é um componente da aplicação, como o servidor HTTP, o pool de conexões do banco de dados ou um worker.
+//Start deve retornar assim que o componente estiver pronto, deixando o trabalho contínuo em uma goroutine, e Stop
+//deve liberar os recursos respeitando o ctx. Qualquer um dos dois pode ser nil. Cada chamada tem o prazo de Timeout
+//ou, se for zero, de TIMEOUT
+type Hook struct {
+	Name    string
+	Start   func(ctx context.Context) error
+	Stop    func(ctx context.Context) error
+	Timeout time.Duration
+}
+
+//HookError identifica o componente e a etapa (start ou stop) que falhou
+type HookError struct {
+	Name string
+	Op   string
+	Err  error
+}
+
+func (e *HookError) Error() string {
+	return e.Op + " " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+//Errors agrega os erros de vários componentes. errors.Is e errors.As verificam cada um deles
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Errors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+//append adiciona err, abrindo outro Errors para que o resultado não tenha níveis
+func (e Errors) append(err error) Errors {
+	var errs Errors
+	if errors.As(err, &errs) {
+		return append(e, errs...)
+	}
+	if err != nil {
+		return append(e, err)
+	}
+	return e
+}
+
+//err retorna nil quando não há erros, evitando um error não nil com um Errors vazio
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+//Lifecycle inicia os componentes na ordem em que foram adicionados e os encerra na ordem inversa, então um
+//componente pode usar os que foram adicionados antes dele até o fim do seu encerramento
+type Lifecycle struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+}
+
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{}
+}
+
+//Append adiciona um componente. Os componentes adicionados depois de Start são iniciados na próxima chamada de Start
+func (lc *Lifecycle) Append(h Hook) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.hooks = append(lc.hooks, h)
+}
+
+//Start inicia os componentes em ordem. Se um deles falhar, os que já foram iniciados são encerrados e o erro
+//retornado inclui a falha do início e as do encerramento
+func (lc *Lifecycle) Start(ctx context.Context) error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	for ; lc.started < len(lc.hooks); lc.started++ {
+		h := lc.hooks[lc.started]
+		if h.Start == nil {
+			continue
+		}
+		if err := call(ctx, h.Timeout, h.Start); err != nil {
+			errs := Errors{&HookError{Name: h.Name, Op: "start", Err: err}}
+			return errs.append(lc.stop(ctx)).err()
+		}
+	}
+	return nil
+}
+
+//Stop encerra os componentes iniciados, na ordem inversa. A falha de um componente não impede o encerramento dos
+//outros, e o erro retornado é um Errors com um HookError para cada falha
+func (lc *Lifecycle) Stop(ctx context.Context) error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return lc.stop(ctx)
+}
+
+func (lc *Lifecycle) stop(ctx context.Context) error {
+	var errs Errors
+	for ; lc.started > 0; lc.started-- {
+		h := lc.hooks[lc.started-1]
+		if h.Stop == nil {
+			continue
+		}
+		if err := call(ctx, h.Timeout, h.Stop); err != nil {
+			errs = append(errs, &HookError{Name: h.Name, Op: "stop", Err: err})
+		}
+	}
+	return errs.err()
+}
+
+//call executa fn com o prazo de timeout. Se o prazo acabar, retorna sem esperar fn, já que alguns recursos, como
+//o *sql.DB, não recebem um contexto ao serem fechados
+func call(parent context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		timeout = TIMEOUT
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil && parent.Err() == nil {
+		return fmt.Errorf("%w after %s", ErrHookTimeout, timeout)
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	//hook registra em calls as chamadas de start e stop, retornando os erros informados
+	hook := func(calls *[]string, name string, errStart, errStop error) Hook {
+		return Hook{
+			Name: name,
+			Start: func(ctx context.Context) error {
+				*calls = append(*calls, "start "+name)
+				return errStart
+			},
+			Stop: func(ctx context.Context) error {
+				*calls = append(*calls, "stop "+name)
+				return errStop
+			},
+		}
+	}
+	t.Run("encerra na ordem inversa do início", func(t *testing.T) {
+		var calls []string
+		lc := NewLifecycle()
+		lc.Append(hook(&calls, "tracing", nil, nil))
+		lc.Append(hook(&calls, "db", nil, nil))
+		lc.Append(Hook{Name: "cache"})
+		lc.Append(hook(&calls, "http", nil, nil))
+		assert.Nil(t, lc.Start(ctx))
+		assert.Nil(t, lc.Stop(ctx))
+		assert.Equal(t, []string{"start tracing", "start db", "start http", "stop http", "stop db", "stop tracing"}, calls)
+		calls = nil
+		assert.Nil(t, lc.Stop(ctx))
+		assert.Empty(t, calls)
+	})
+	t.Run("falha no início encerra os componentes já iniciados", func(t *testing.T) {
+		var calls []string
+		lc := NewLifecycle()
+		lc.Append(hook(&calls, "db", nil, nil))
+		lc.Append(hook(&calls, "http", errors.New("address already in use"), nil))
+		lc.Append(hook(&calls, "worker", nil, nil))
+		err := lc.Start(ctx)
+		assert.EqualError(t, err, "start http: address already in use")
+		assert.Equal(t, []string{"start db", "start http", "stop db"}, calls)
+	})
+	t.Run("agrega os erros do encerramento", func(t *testing.T) {
+		var calls []string
+		errDB := errors.New("sql: database is closed")
+		lc := NewLifecycle()
+		lc.Append(hook(&calls, "tracing", nil, errors.New("exporter unreachable")))
+		lc.Append(hook(&calls, "db", nil, errDB))
+		lc.Append(hook(&calls, "http", nil, nil))
+		assert.Nil(t, lc.Start(ctx))
+		err := lc.Stop(ctx)
+		assert.EqualError(t, err, "stop db: sql: database is closed; stop tracing: exporter unreachable")
+		assert.ErrorIs(t, err, errDB)
+		var hookErr *HookError
+		assert.ErrorAs(t, err, &hookErr)
+		assert.Equal(t, "db", hookErr.Name)
+		assert.Equal(t, []string{"start tracing", "start db", "start http", "stop http", "stop db", "stop tracing"}, calls)
+	})
+	t.Run("prazo de cada componente", func(t *testing.T) {
+		var calls []string
+		lc := NewLifecycle()
+		lc.Append(hook(&calls, "db", nil, nil))
+		lc.Append(Hook{
+			Name:    "worker",
+			Stop:    func(ctx context.Context) error { time.Sleep(time.Second); return nil },
+			Timeout: 10 * time.Millisecond,
+		})
+		lc.Append(Hook{
+			Name:    "http",
+			Stop:    func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() },
+			Timeout: 10 * time.Millisecond,
+		})
+		assert.Nil(t, lc.Start(ctx))
+		err := lc.Stop(ctx)
+		assert.ErrorIs(t, err, ErrHookTimeout)
+		assert.EqualError(t, err, "stop http: hook timed out after 10ms; stop worker: hook timed out after 10ms")
+		assert.Equal(t, []string{"start db", "stop db"}, calls)
+	})
+	t.Run("contexto cancelado", func(t *testing.T) {
+		lc := NewLifecycle()
+		lc.Append(Hook{
+			Name: "http",
+			Stop: func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() },
+		})
+		assert.Nil(t, lc.Start(ctx))
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.EqualError(t, lc.Stop(canceled), "stop http: context canceled")
+	})
+}
@@ -0,0 +1,395 @@
+/*
+Package config carrega a configuração da API.
+
+Os valores são lidos, em ordem de precedência crescente, dos valores padrão, de um arquivo
+YAML ou JSON opcional e das variáveis de ambiente. Assim o mesmo binário pode ser executado
+em vários ambientes mudando apenas o arquivo ou as variáveis.
+*/
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//ErrInvalidConfig é retornado quando algum campo obrigatório não foi informado ou possui valor inválido
+var ErrInvalidConfig = errors.New("invalid config")
+
+//langPattern é o mesmo formato aceito por weather.Options.Validate
+var langPattern = regexp.MustCompile(`^[a-zA-Z]{2}(_[a-zA-Z]{2})?$`)
+
+type Config struct {
+	Port           string `yaml:"port" json:"port"`
+	MigrateOnStart bool   `yaml:"migrate_on_start" json:"migrate_on_start"`
+	//SeedFile é um script SQL com dados de exemplo, executado depois das migrações quando MigrateOnStart está habilitado
+	SeedFile string `yaml:"seed_file" json:"seed_file"`
+	//ShutdownDelay é o tempo entre o /readyz passar a responder 503 e o servidor começar a encerrar as conexões
+	ShutdownDelay Duration `yaml:"shutdown_delay" json:"shutdown_delay"`
+	DB            DB       `yaml:"db" json:"db"`
+	Weather       Weather  `yaml:"weather" json:"weather"`
+	Tracing       Tracing  `yaml:"tracing" json:"tracing"`
+}
+
+type DB struct {
+	//Driver pode ser mysql, postgres, sqlite ou inmem
+	Driver   string `yaml:"driver" json:"driver"`
+	Host     string `yaml:"host" json:"host"`
+	Port     string `yaml:"port" json:"port"`
+	User     string `yaml:"user" json:"user"`
+	Password string `yaml:"password" json:"password"`
+	Name     string `yaml:"name" json:"name"`
+	//Path é o arquivo do banco de dados quando o driver é sqlite
+	Path string `yaml:"path" json:"path"`
+	//SSLMode é o sslmode da conexão quando o driver é postgres, como disable, require ou verify-full
+	SSLMode string `yaml:"ssl_mode" json:"ssl_mode"`
+}
+
+type Tracing struct {
+	//Exporter define para onde os spans são enviados: none, stdout ou otlp. O endereço do OTLP
+	//é configurado pelas variáveis padrão do OpenTelemetry, como OTEL_EXPORTER_OTLP_ENDPOINT
+	Exporter string `yaml:"exporter" json:"exporter"`
+}
+
+type Weather struct {
+	//Provider é o provedor principal: openweather, openmeteo ou static
+	Provider string `yaml:"provider" json:"provider"`
+	//Fallback são os provedores consultados, em ordem, quando o principal está indisponível
+	Fallback []string `yaml:"fallback" json:"fallback"`
+	//APIKey é a chave da OpenWeather, obrigatória apenas quando ela é usada
+	APIKey string `yaml:"api_key" json:"api_key"`
+	//BaseURL substitui o endereço da OpenWeather, por exemplo pelo servidor de cmd/fakeweather
+	BaseURL string `yaml:"base_url" json:"base_url"`
+	//Units são as unidades padrão das respostas: metric, imperial ou standard
+	Units string `yaml:"units" json:"units"`
+	//Lang é o idioma padrão das descrições, como pt_br ou en
+	Lang string `yaml:"lang" json:"lang"`
+	//Timeout é o tempo máximo de cada chamada à API
+	Timeout Duration `yaml:"timeout" json:"timeout"`
+	//MaxRetries é a quantidade de novas tentativas quando a API falha com timeout, 429 ou 5xx
+	MaxRetries int `yaml:"max_retries" json:"max_retries"`
+	//CacheTTL é o tempo que uma resposta fica no cache. Zero desabilita o cache
+	CacheTTL Duration `yaml:"cache_ttl" json:"cache_ttl"`
+	//CacheSize é a quantidade máxima de coordenadas guardadas no cache
+	CacheSize int `yaml:"cache_size" json:"cache_size"`
+}
+
+//Load carrega a configuração a partir do arquivo em path (opcional, pode ser vazio) e das variáveis de ambiente
+func Load(path string) (*Config, error) {
+	c, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	err = c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+//LoadDB carrega a configuração da mesma forma que Load, mas valida apenas o banco de dados. É usado pelo comando
+//migrate, que não depende dos provedores de previsão do tempo e portanto não exige, por exemplo, o API_KEY
+func LoadDB(path string) (*Config, error) {
+	c, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	err = c.DB.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func read(path string) (*Config, error) {
+	c := defaults()
+	if path != "" {
+		err := c.loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := c.loadEnv()
+	if err != nil {
+		return nil, err
+	}
+	c.applyDriverDefaults()
+	return c, nil
+}
+
+func defaults() *Config {
+	return &Config{
+		Port: "8000",
+		DB: DB{
+			Driver:  "mysql",
+			Host:    "localhost",
+			Name:    "workshop",
+			Path:    "workshop.db",
+			SSLMode: "disable",
+		},
+		ShutdownDelay: Duration(5 * time.Second),
+		Weather: Weather{
+			Provider:   "openweather",
+			Units:      "metric",
+			Lang:       "pt_br",
+			Timeout:    Duration(time.Second),
+			MaxRetries: 2,
+			CacheTTL:   Duration(10 * time.Minute),
+			CacheSize:  1000,
+		},
+		Tracing: Tracing{
+			Exporter: "none",
+		},
+	}
+}
+
+func (c *Config) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, c)
+	case ".json":
+		err = json.Unmarshal(content, c)
+	default:
+		return fmt.Errorf("%w: unsupported config file extension %q", ErrInvalidConfig, filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+//loadEnv sobrescreve os valores com as variáveis de ambiente que estiverem definidas
+func (c *Config) loadEnv() error {
+	vars := map[string]*string{
+		"PORT":             &c.Port,
+		"DB_DRIVER":        &c.DB.Driver,
+		"DB_HOST":          &c.DB.Host,
+		"DB_PORT":          &c.DB.Port,
+		"DB_USER":          &c.DB.User,
+		"DB_PASSWORD":      &c.DB.Password,
+		"DB_NAME":          &c.DB.Name,
+		"DB_PATH":          &c.DB.Path,
+		"DB_SSLMODE":       &c.DB.SSLMode,
+		"API_KEY":          &c.Weather.APIKey,
+		"WEATHER_UNITS":    &c.Weather.Units,
+		"WEATHER_LANG":     &c.Weather.Lang,
+		"WEATHER_PROVIDER": &c.Weather.Provider,
+		"WEATHER_BASE_URL": &c.Weather.BaseURL,
+		"TRACING_EXPORTER": &c.Tracing.Exporter,
+		"SEED_FILE":        &c.SeedFile,
+	}
+	for name, field := range vars {
+		if v, ok := os.LookupEnv(name); ok {
+			*field = v
+		}
+	}
+	durations := map[string]*Duration{
+		"SHUTDOWN_DELAY":    &c.ShutdownDelay,
+		"WEATHER_TIMEOUT":   &c.Weather.Timeout,
+		"WEATHER_CACHE_TTL": &c.Weather.CacheTTL,
+	}
+	for name, field := range durations {
+		if v, ok := os.LookupEnv(name); ok {
+			err := field.parse(v)
+			if err != nil {
+				return fmt.Errorf("%w: %s must be a duration like 10m", ErrInvalidConfig, name)
+			}
+		}
+	}
+	ints := map[string]*int{
+		"WEATHER_MAX_RETRIES": &c.Weather.MaxRetries,
+		"WEATHER_CACHE_SIZE":  &c.Weather.CacheSize,
+	}
+	for name, field := range ints {
+		if v, ok := os.LookupEnv(name); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("%w: %s must be an integer", ErrInvalidConfig, name)
+			}
+			*field = n
+		}
+	}
+	if v, ok := os.LookupEnv("WEATHER_FALLBACK"); ok {
+		c.Weather.Fallback = nil
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				c.Weather.Fallback = append(c.Weather.Fallback, name)
+			}
+		}
+	}
+	if v, ok := os.LookupEnv("MIGRATE_ON_START"); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%w: MIGRATE_ON_START must be a boolean", ErrInvalidConfig)
+		}
+		c.MigrateOnStart = b
+	}
+	return nil
+}
+
+//applyDriverDefaults preenche os valores que dependem do driver escolhido
+func (c *Config) applyDriverDefaults() {
+	if c.DB.Port != "" {
+		return
+	}
+	switch c.DB.Driver {
+	case "mysql":
+		c.DB.Port = "3306"
+	case "postgres":
+		c.DB.Port = "5432"
+	}
+}
+
+//Validate verifica se todos os campos obrigatórios foram informados
+func (c *Config) Validate() error {
+	var missing []string
+	if c.Port == "" {
+		missing = append(missing, "port")
+	}
+	for _, p := range c.Weather.Providers() {
+		switch p {
+		case "openweather", "openmeteo", "static":
+		default:
+			return fmt.Errorf("%w: unknown weather provider %q", ErrInvalidConfig, p)
+		}
+	}
+	if c.Weather.uses("openweather") && c.Weather.APIKey == "" {
+		missing = append(missing, "weather.api_key")
+	}
+	switch c.Weather.Units {
+	case "metric", "imperial", "standard":
+	default:
+		return fmt.Errorf("%w: weather.units must be metric, imperial or standard", ErrInvalidConfig)
+	}
+	if !langPattern.MatchString(c.Weather.Lang) {
+		return fmt.Errorf("%w: weather.lang must be a language code like en or pt_br", ErrInvalidConfig)
+	}
+	if c.Weather.BaseURL != "" {
+		u, err := url.Parse(c.Weather.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: weather.base_url must be an http or https URL", ErrInvalidConfig)
+		}
+	}
+	if c.ShutdownDelay < 0 {
+		return fmt.Errorf("%w: shutdown_delay must not be negative", ErrInvalidConfig)
+	}
+	if c.Weather.Timeout <= 0 {
+		return fmt.Errorf("%w: weather.timeout must be positive", ErrInvalidConfig)
+	}
+	if c.Weather.MaxRetries < 0 || c.Weather.CacheTTL < 0 || c.Weather.CacheSize < 0 {
+		return fmt.Errorf("%w: weather.max_retries, weather.cache_ttl and weather.cache_size must not be negative", ErrInvalidConfig)
+	}
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		return fmt.Errorf("%w: tracing.exporter must be none, stdout or otlp", ErrInvalidConfig)
+	}
+	dbMissing, err := c.DB.missing()
+	if err != nil {
+		return err
+	}
+	return missingError(append(missing, dbMissing...))
+}
+
+//Validate verifica apenas os campos do banco de dados
+func (d DB) Validate() error {
+	missing, err := d.missing()
+	if err != nil {
+		return err
+	}
+	return missingError(missing)
+}
+
+//missing retorna os campos obrigatórios para o driver que não foram informados
+func (d DB) missing() ([]string, error) {
+	var missing []string
+	switch d.Driver {
+	case "mysql", "postgres":
+		for name, v := range map[string]string{
+			"db.host":     d.Host,
+			"db.user":     d.User,
+			"db.password": d.Password,
+			"db.name":     d.Name,
+		} {
+			if v == "" {
+				missing = append(missing, name)
+			}
+		}
+	case "sqlite":
+		if d.Path == "" {
+			missing = append(missing, "db.path")
+		}
+	case "inmem":
+	default:
+		return nil, fmt.Errorf("%w: unknown db.driver %q", ErrInvalidConfig, d.Driver)
+	}
+	return missing, nil
+}
+
+func missingError(missing []string) error {
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("%w: missing %s", ErrInvalidConfig, strings.Join(missing, ", "))
+}
+
+//Providers retorna o provedor principal seguido dos alternativos
+func (w Weather) Providers() []string {
+	return append([]string{w.Provider}, w.Fallback...)
+}
+
+func (w Weather) uses(provider string) bool {
+	for _, p := range w.Providers() {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+//DSN retorna a string de conexão no formato esperado pelo driver de banco de dados
+func (d DB) DSN() string {
+	switch d.Driver {
+	case "mysql":
+		return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", d.User, d.Password, d.Host, d.Port, d.Name)
+	case "postgres":
+		u := url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(d.User, d.Password),
+			Host:   net.JoinHostPort(d.Host, d.Port),
+			Path:   "/" + d.Name,
+		}
+		if d.SSLMode != "" {
+			u.RawQuery = url.Values{"sslmode": {d.SSLMode}}.Encode()
+		}
+		return u.String()
+	case "sqlite":
+		return d.Path
+	default:
+		return ""
+	}
+}
+
+//SQLDriver retorna o nome do driver registrado no database/sql
+func (d DB) SQLDriver() string {
+	if d.Driver == "sqlite" {
+		return "sqlite3"
+	}
+	return d.Driver
+}
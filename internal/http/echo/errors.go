@@ -0,0 +1,70 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PicPay/go-test-workshop/internal/logging"
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/labstack/echo/v4"
+)
+
+//errorResponse é o corpo padrão de todas as respostas de erro da API
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+//httpError traduz os erros do domínio para o status HTTP correspondente e escreve a resposta em JSON
+func httpError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, person.ErrNotFound):
+		return jsonError(c, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, person.ErrInvalidPerson):
+		return jsonError(c, http.StatusUnprocessableEntity, "invalid_person", err.Error())
+	case errors.Is(err, person.ErrConflict):
+		return jsonError(c, http.StatusConflict, "conflict", err.Error())
+	case errors.Is(err, person.ErrInvalidListOptions):
+		return jsonError(c, http.StatusBadRequest, "bad_request", err.Error())
+	case errors.Is(err, weather.ErrInvalidCoord):
+		return jsonError(c, http.StatusBadRequest, "invalid_coordinates", err.Error())
+	case errors.Is(err, weather.ErrInvalidQuery):
+		return jsonError(c, http.StatusBadRequest, "invalid_query", err.Error())
+	case errors.Is(err, weather.ErrInvalidOptions):
+		return jsonError(c, http.StatusBadRequest, "invalid_options", err.Error())
+	case errors.Is(err, weather.ErrUnsupported):
+		return jsonError(c, http.StatusNotImplemented, "not_supported", err.Error())
+	case errors.Is(err, weather.ErrNotFound):
+		return jsonError(c, http.StatusNotFound, "location_not_found", upstreamMessage(err))
+	case errors.Is(err, weather.ErrBadRequest):
+		return jsonError(c, http.StatusBadRequest, "invalid_location", upstreamMessage(err))
+	case errors.Is(err, weather.ErrRateLimited):
+		return jsonError(c, http.StatusTooManyRequests, "rate_limited", upstreamMessage(err))
+	case errors.Is(err, weather.ErrUnavailable):
+		return jsonError(c, http.StatusServiceUnavailable, "weather_unavailable", upstreamMessage(err))
+	case errors.Is(err, weather.ErrUnauthorized), errors.Is(err, weather.ErrUnexpected):
+		//a chave recusada ou a resposta inválida são problemas da API, não do cliente, então o detalhe vai só para o log
+		logging.FromContext(c.Request().Context()).Error("weather provider error", "error", err.Error())
+		return jsonError(c, http.StatusBadGateway, "bad_gateway", "weather provider returned an invalid response")
+	default:
+		logging.FromContext(c.Request().Context()).Error("unexpected error", "error", err.Error())
+		return jsonError(c, http.StatusInternalServerError, "internal_error", err.Error())
+	}
+}
+
+//upstreamMessage retorna a mensagem enviada pela API de previsão do tempo, quando existir
+func upstreamMessage(err error) string {
+	var ue *weather.UpstreamError
+	if errors.As(err, &ue) && ue.Message != "" {
+		return ue.Message
+	}
+	return err.Error()
+}
+
+func jsonError(c echo.Context, status int, code, msg string) error {
+	return c.JSON(status, errorResponse{
+		Error:   code,
+		Message: msg,
+	})
+}
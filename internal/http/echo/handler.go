@@ -4,17 +4,32 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/PicPay/go-test-workshop/internal/logging"
 	"github.com/PicPay/go-test-workshop/person"
 	"github.com/PicPay/go-test-workshop/weather"
-	logger "github.com/PicPay/lib-go-logger"
 	"github.com/labstack/echo/v4"
 )
 
-func Handlers(l *logger.Logger, pService person.UseCase, wService weather.UseCase) *echo.Echo {
+//Handlers registra as rotas da API. Todas as respostas recebem o cabeçalho X-Request-ID e, se l não for nil,
+//cada requisição é registrada no log e os handlers e serviços recebem um logger com o request_id no contexto
+func Handlers(l logging.Logger, pService person.UseCase, wService weather.UseCase) *echo.Echo {
 	e := echo.New()
+	e.Use(RequestID())
+	if l != nil {
+		e.Use(AccessLog(l))
+	}
 	e.GET("/hello", Hello)
 	e.GET("/hello/:lastname", GetUser(pService))
 	e.GET("/weather/:lat/:long", Weather(wService))
+	e.GET("/weather/:lat/:long/forecast", Forecast(wService))
+	e.GET("/weather/city/:city", WeatherByCity(wService))
+	e.GET("/weather/zip/:zip", WeatherByZip(wService))
+	e.GET("/people", ListPeople(pService))
+	e.POST("/people", CreatePerson(pService))
+	e.GET("/people/:id", GetPerson(pService))
+	e.PUT("/people/:id", UpdatePerson(pService))
+	e.PATCH("/people/:id", PatchPerson(pService))
+	e.DELETE("/people/:id", DeletePerson(pService))
 	return e
 }
 
@@ -25,25 +40,76 @@ func Hello(c echo.Context) error {
 func GetUser(s person.UseCase) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		lastname := c.Param("lastname")
-		people, err := s.Search(lastname)
+		page, err := s.Search(c.Request().Context(), lastname, person.ListOptions{Limit: 1})
 		if err != nil {
-			return c.String(http.StatusInternalServerError, err.Error())
+			return httpError(c, err)
 		}
-		if len(people) == 0 {
-			return c.String(http.StatusNotFound, "not found")
+		if len(page.People) == 0 {
+			return httpError(c, person.ErrNotFound)
 		}
-		return c.String(http.StatusOK, fmt.Sprintf("Hello %s %s", people[0].Name, people[0].LastName))
+		p := page.People[0]
+		return c.String(http.StatusOK, fmt.Sprintf("Hello %s %s", p.Name, p.LastName))
 	}
 }
 
 func Weather(s weather.UseCase) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		lat := c.Param("lat")
-		long := c.Param("long")
-		w, err := s.Get(lat, long)
+		coord, err := weather.ParseCoord(c.Param("lat"), c.Param("long"))
+		if err != nil {
+			return httpError(c, err)
+		}
+		w, err := s.Get(c.Request().Context(), coord, weatherOptions(c))
 		if err != nil {
-			return c.String(http.StatusInternalServerError, err.Error())
+			return httpError(c, err)
 		}
 		return c.JSON(http.StatusOK, w)
 	}
 }
+
+//WeatherByCity busca as condições pelo nome da cidade, por exemplo /weather/city/Florianópolis,BR
+func WeatherByCity(s weather.UseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		w, err := s.ByCity(c.Request().Context(), c.Param("city"), weatherOptions(c))
+		if err != nil {
+			return httpError(c, err)
+		}
+		return c.JSON(http.StatusOK, w)
+	}
+}
+
+//WeatherByZip busca as condições pelo código postal. O país é informado em ?country= e, se omitido, é BR
+func WeatherByZip(s weather.UseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		country := c.QueryParam("country")
+		if country == "" {
+			country = "BR"
+		}
+		w, err := s.ByZip(c.Request().Context(), c.Param("zip"), country, weatherOptions(c))
+		if err != nil {
+			return httpError(c, err)
+		}
+		return c.JSON(http.StatusOK, w)
+	}
+}
+
+func Forecast(s weather.UseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		coord, err := weather.ParseCoord(c.Param("lat"), c.Param("long"))
+		if err != nil {
+			return httpError(c, err)
+		}
+		f, err := s.Forecast(c.Request().Context(), coord, weatherOptions(c))
+		if err != nil {
+			return httpError(c, err)
+		}
+		return c.JSON(http.StatusOK, f)
+	}
+}
+
+//weatherOptions lê as unidades e o idioma dos parâmetros ?units= e ?lang=. A validação é feita pelo weather.UseCase
+func weatherOptions(c echo.Context) weather.Options {
+	return weather.Options{
+		Units: weather.Units(c.QueryParam("units")),
+		Lang:  c.QueryParam("lang"),
+	}
+}
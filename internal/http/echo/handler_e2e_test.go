@@ -8,6 +8,7 @@ import (
 	"github.com/PicPay/go-test-workshop/internal/http/echo"
 	"github.com/PicPay/go-test-workshop/person"
 	"github.com/PicPay/go-test-workshop/person/mysql"
+	"github.com/PicPay/go-test-workshop/person/persontest"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"net/http"
@@ -18,7 +19,7 @@ import (
 func TestGetUserE2E(t *testing.T) {
 	//fase: Configure os dados de teste
 	ctx := context.Background()
-	container, err := person.SetupMysqL(ctx)
+	container, err := persontest.SetupMysqL(ctx)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -28,15 +29,15 @@ func TestGetUserE2E(t *testing.T) {
 		t.Error(err)
 	}
 	defer db.Close()
-	err = person.InitMySQL(ctx, db)
+	err = persontest.InitMySQL(ctx, db)
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer person.TruncateMySQL(ctx, db)
+	defer persontest.TruncateMySQL(ctx, db)
 
 	repo := mysql.NewMySQL(db)
 	service := person.NewService(repo)
-	_, err = service.Create("Ronnie", "Dio")
+	_, err = service.Create(ctx, "Ronnie", "Dio")
 	assert.Nil(t, err)
 
 	//fase: Invoque o método sendo testado
@@ -4,17 +4,21 @@ package echo_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/PicPay/go-test-workshop/internal/http/echo"
 	"github.com/PicPay/go-test-workshop/person"
 	person_mock "github.com/PicPay/go-test-workshop/person/mocks"
 	weather "github.com/PicPay/go-test-workshop/weather"
+	"github.com/PicPay/go-test-workshop/weather/fake"
 	weather_mock "github.com/PicPay/go-test-workshop/weather/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestHello(t *testing.T) {
@@ -40,8 +44,8 @@ func TestGetUser(t *testing.T) {
 			},
 		}
 		s := person_mock.NewUseCase(t)
-		s.On("Search", "dio").
-			Return(p, nil).
+		s.On("Search", mock.Anything, "dio", person.ListOptions{Limit: 1}).
+			Return(&person.Page{People: p, Total: 1, Limit: 1}, nil).
 			Once()
 		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
 		c.SetPath("/hello/:lastname")
@@ -57,8 +61,8 @@ func TestGetUser(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		rec := httptest.NewRecorder()
 		s := person_mock.NewUseCase(t)
-		s.On("Search", "dio").
-			Return([]*person.Person{}, nil).
+		s.On("Search", mock.Anything, "dio", person.ListOptions{Limit: 1}).
+			Return(&person.Page{People: []*person.Person{}, Total: 1, Limit: 1, Offset: 0}, nil).
 			Once()
 		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
 		c.SetPath("/hello/:lastname")
@@ -69,12 +73,30 @@ func TestGetUser(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, http.StatusNotFound, rec.Code)
 	})
+	t.Run("status not found no repositório", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("Search", mock.Anything, "dio", person.ListOptions{Limit: 1}).
+			Return(nil, fmt.Errorf("erro buscando person do repositório: %w", person.ErrNotFound)).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/hello/:lastname")
+		c.SetParamNames("lastname")
+		c.SetParamValues("dio")
+		h := echo.GetUser(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"error":"not_found","message":"erro buscando person do repositório: not found"}`, rec.Body.String())
+	})
 
 }
 
 func TestWeather(t *testing.T) {
 	lat := "-27.5969"
 	long := "-48.5495"
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
 	req, _ := http.NewRequest("GET", "/weather", nil)
 	t.Run("status ok", func(t *testing.T) {
 		rec := httptest.NewRecorder()
@@ -98,7 +120,7 @@ func TestWeather(t *testing.T) {
 			Name: "Florianópolis",
 		}
 		s := weather_mock.NewUseCase(t)
-		s.On("Get", lat, long).
+		s.On("Get", mock.Anything, coord, weather.Options{}).
 			Return(city, nil).
 			Once()
 		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
@@ -118,7 +140,7 @@ func TestWeather(t *testing.T) {
 	t.Run("status error", func(t *testing.T) {
 		rec := httptest.NewRecorder()
 		s := weather_mock.NewUseCase(t)
-		s.On("Get", lat, long).
+		s.On("Get", mock.Anything, coord, weather.Options{}).
 			Return(nil, fmt.Errorf("Not found")).
 			Once()
 		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
@@ -130,5 +152,266 @@ func TestWeather(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	})
+	t.Run("coordenadas inválidas", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s := weather_mock.NewUseCase(t)
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/weather/:lat/:long")
+		c.SetParamNames("lat", "long")
+		c.SetParamValues("-95", long)
+		h := echo.Weather(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error":"invalid_coordinates","message":"invalid coordinates: latitude must be between -90 and 90"}`, rec.Body.String())
+	})
+	t.Run("unidades e idioma", func(t *testing.T) {
+		s := weather_mock.NewUseCase(t)
+		s.On("Get", mock.Anything, coord, weather.Options{Units: weather.UnitsImperial, Lang: "en"}).
+			Return(&weather.Weather{Name: "Florianópolis", Units: weather.UnitsImperial}, nil).
+			Once()
+		req := httptest.NewRequest(http.MethodGet, "/weather/-27.5969/-48.5495?units=imperial&lang=en", nil)
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, s).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"units":"imperial"`)
+	})
+	t.Run("unidades inválidas", func(t *testing.T) {
+		s := weather_mock.NewUseCase(t)
+		s.On("Get", mock.Anything, coord, weather.Options{Units: "kelvin"}).
+			Return(nil, fmt.Errorf("%w: units must be metric, imperial or standard", weather.ErrInvalidOptions)).
+			Once()
+		req := httptest.NewRequest(http.MethodGet, "/weather/-27.5969/-48.5495?units=kelvin", nil)
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, s).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error":"invalid_options","message":"invalid weather options: units must be metric, imperial or standard"}`, rec.Body.String())
+	})
+	upstream := []struct {
+		name     string
+		err      error
+		status   int
+		expected string
+	}{
+		{
+			name:     "chave inválida",
+			err:      &weather.UpstreamError{Kind: weather.ErrUnauthorized, StatusCode: 401, Message: "Invalid API key"},
+			status:   http.StatusBadGateway,
+			expected: `{"error":"bad_gateway","message":"weather provider returned an invalid response"}`,
+		},
+		{
+			name:     "localização não encontrada",
+			err:      &weather.UpstreamError{Kind: weather.ErrNotFound, StatusCode: 404, Message: "city not found"},
+			status:   http.StatusNotFound,
+			expected: `{"error":"location_not_found","message":"city not found"}`,
+		},
+		{
+			name:     "limite de requisições",
+			err:      &weather.UpstreamError{Kind: weather.ErrRateLimited, StatusCode: 429, Message: "rate limit"},
+			status:   http.StatusTooManyRequests,
+			expected: `{"error":"rate_limited","message":"rate limit"}`,
+		},
+		{
+			name:     "api fora do ar",
+			err:      &weather.UpstreamError{Kind: weather.ErrUnavailable, StatusCode: 500},
+			status:   http.StatusServiceUnavailable,
+			expected: `{"error":"weather_unavailable","message":"weather api: unavailable: 500"}`,
+		},
+		{
+			name:     "resposta inválida",
+			err:      &weather.UpstreamError{Kind: weather.ErrUnexpected, StatusCode: 200, Message: "invalid response body", Err: errors.New("invalid character '<' looking for beginning of value")},
+			status:   http.StatusBadGateway,
+			expected: `{"error":"bad_gateway","message":"weather provider returned an invalid response"}`,
+		},
+	}
+	for _, tt := range upstream {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s := weather_mock.NewUseCase(t)
+			s.On("Get", mock.Anything, coord, weather.Options{}).
+				Return(nil, tt.err).
+				Once()
+			c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+			c.SetPath("/weather/:lat/:long")
+			c.SetParamNames("lat", "long")
+			c.SetParamValues(lat, long)
+			h := echo.Weather(s)
+			err := h(c)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.JSONEq(t, tt.expected, rec.Body.String())
+		})
+	}
+}
+
+func TestForecast(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/weather", nil)
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+	t.Run("status ok", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		forecast := &weather.Forecast{
+			City: weather.City{Name: "Florianópolis", Country: "BR", Coord: coord},
+			List: []weather.ForecastItem{
+				{
+					Time:       time.Date(2022, 6, 21, 18, 0, 0, 0, time.FixedZone("", -3*60*60)),
+					Conditions: []weather.Condition{{ID: 500, Main: "Rain", Description: "chuva leve", Icon: "10n"}},
+					Main:       weather.Main{Temp: 18.74, Humidity: 93},
+					Wind:       weather.Wind{Speed: 3.09, Deg: 57},
+				},
+			},
+		}
+		s := weather_mock.NewUseCase(t)
+		s.On("Forecast", mock.Anything, coord, weather.Options{}).
+			Return(forecast, nil).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/weather/:lat/:long/forecast")
+		c.SetParamNames("lat", "long")
+		c.SetParamValues("-27.5969", "-48.5495")
+		h := echo.Forecast(s)
+		err := h(c)
+		assert.Nil(t, err)
+
+		expected, err := json.Marshal(forecast)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, string(expected), rec.Body.String())
+	})
+	t.Run("coordenadas inválidas", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s := weather_mock.NewUseCase(t)
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/weather/:lat/:long/forecast")
+		c.SetParamNames("lat", "long")
+		c.SetParamValues("-27.5969", "abc")
+		h := echo.Forecast(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("api fora do ar", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s := weather_mock.NewUseCase(t)
+		s.On("Forecast", mock.Anything, coord, weather.Options{}).
+			Return(nil, &weather.UpstreamError{Kind: weather.ErrUnavailable, StatusCode: 503}).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/weather/:lat/:long/forecast")
+		c.SetParamNames("lat", "long")
+		c.SetParamValues("-27.5969", "-48.5495")
+		h := echo.Forecast(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
+
+func TestWeatherByCity(t *testing.T) {
+	city := &weather.Weather{Coord: weather.Coord{Lat: -27.5969, Lon: -48.5495}, Name: "Florianópolis"}
+	t.Run("status ok", func(t *testing.T) {
+		s := weather_mock.NewUseCase(t)
+		s.On("ByCity", mock.Anything, "Florianópolis,BR", weather.Options{}).
+			Return(city, nil).
+			Once()
+		req := httptest.NewRequest(http.MethodGet, "/weather/city/Florian%C3%B3polis,BR", nil)
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, s).ServeHTTP(rec, req)
+
+		expected, err := json.Marshal(city)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, string(expected), rec.Body.String())
+	})
+	t.Run("cidade não encontrada", func(t *testing.T) {
+		s := weather_mock.NewUseCase(t)
+		s.On("ByCity", mock.Anything, "Atlantida", weather.Options{}).
+			Return(nil, &weather.UpstreamError{Kind: weather.ErrNotFound, StatusCode: 404, Message: "city not found"}).
+			Once()
+		req := httptest.NewRequest(http.MethodGet, "/weather/city/Atlantida", nil)
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, s).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"error":"location_not_found","message":"city not found"}`, rec.Body.String())
+	})
+}
+
+func TestWeatherByZip(t *testing.T) {
+	city := &weather.Weather{Coord: weather.Coord{Lat: -27.5969, Lon: -48.5495}, Name: "Florianópolis"}
+	t.Run("país padrão", func(t *testing.T) {
+		s := weather_mock.NewUseCase(t)
+		s.On("ByZip", mock.Anything, "88010-000", "BR", weather.Options{}).
+			Return(city, nil).
+			Once()
+		req := httptest.NewRequest(http.MethodGet, "/weather/zip/88010-000", nil)
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, s).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("país informado", func(t *testing.T) {
+		s := weather_mock.NewUseCase(t)
+		s.On("ByZip", mock.Anything, "94040", "US", weather.Options{}).
+			Return(city, nil).
+			Once()
+		req := httptest.NewRequest(http.MethodGet, "/weather/zip/94040?country=US", nil)
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, s).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("código não encontrado", func(t *testing.T) {
+		s := weather_mock.NewUseCase(t)
+		s.On("ByZip", mock.Anything, "00000-000", "BR", weather.Options{}).
+			Return(nil, &weather.UpstreamError{Kind: weather.ErrNotFound, StatusCode: 404, Message: "city not found"}).
+			Once()
+		req := httptest.NewRequest(http.MethodGet, "/weather/zip/00000-000", nil)
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, s).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"error":"location_not_found","message":"city not found"}`, rec.Body.String())
+	})
+	t.Run("código inválido", func(t *testing.T) {
+		s := weather_mock.NewUseCase(t)
+		s.On("ByZip", mock.Anything, "x", "BR", weather.Options{}).
+			Return(nil, fmt.Errorf("%w: zip must have between 3 and 10 characters", weather.ErrInvalidQuery)).
+			Once()
+		req := httptest.NewRequest(http.MethodGet, "/weather/zip/x", nil)
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, s).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error":"invalid_query","message":"invalid location query: zip must have between 3 and 10 characters"}`, rec.Body.String())
+	})
+	t.Run("provedor sem suporte", func(t *testing.T) {
+		s := weather_mock.NewUseCase(t)
+		s.On("ByZip", mock.Anything, "88010-000", "BR", weather.Options{}).
+			Return(nil, fmt.Errorf("%w: open-meteo does not support zip code lookups", weather.ErrUnsupported)).
+			Once()
+		req := httptest.NewRequest(http.MethodGet, "/weather/zip/88010-000", nil)
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, s).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotImplemented, rec.Code)
+		assert.JSONEq(t, `{"error":"not_supported","message":"weather api: not supported by provider: open-meteo does not support zip code lookups"}`, rec.Body.String())
+	})
+}
 
+func TestWeatherFakeServer(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	s := weather.NewService("fake", weather.WithBaseURL(server.BaseURL()))
+	h := echo.Handlers(nil, nil, s)
+	t.Run("status ok", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/weather/-27.5969/-48.5495?units=imperial", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var w weather.Weather
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &w))
+		assert.Equal(t, weather.Coord{Lat: -27.5969, Lon: -48.5495}, w.Coord)
+		assert.Equal(t, weather.UnitsImperial, w.Units)
+	})
+	t.Run("api fora do ar", func(t *testing.T) {
+		server.FailNext(1, http.StatusInternalServerError, "")
+		req := httptest.NewRequest(http.MethodGet, "/weather/-27.5969/-48.5495", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
 }
@@ -0,0 +1,97 @@
+package echo
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/internal/logging"
+	"github.com/labstack/echo/v4"
+)
+
+//HeaderRequestID é o cabeçalho com o id da requisição, recebido do cliente ou gerado pela API
+const HeaderRequestID = echo.HeaderXRequestID
+
+//maxRequestIDLen limita o tamanho do id recebido, que é repetido em todas as linhas de log da requisição
+const maxRequestIDLen = 128
+
+//RequestID retorna o middleware que usa o id recebido em X-Request-ID, ou gera um novo se ele estiver ausente
+//ou for inválido. O id é devolvido no mesmo cabeçalho da resposta e guardado no contexto da requisição
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(HeaderRequestID)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			c.Response().Header().Set(HeaderRequestID, id)
+			c.SetRequest(req.WithContext(logging.WithRequestID(req.Context(), id)))
+			return next(c)
+		}
+	}
+}
+
+//validRequestID aceita apenas letras, números e alguns separadores, para que o valor enviado pelo cliente
+//não possa quebrar as linhas de log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':', r == '/', r == '+', r == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+//AccessLog retorna o middleware que coloca no contexto da requisição um logger com o request_id, usado pelos
+//handlers e serviços através de logging.FromContext, e registra uma linha de log ao final de cada requisição.
+//Deve ser registrado depois de RequestID. O erro é retornado depois de registrado; como a resposta já foi escrita por
+//c.Error, o tratamento de erros do Echo não a altera
+func AccessLog(l logging.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+			rl := l
+			if id := logging.RequestID(req.Context()); id != "" {
+				rl = logging.With(l, "request_id", id)
+			}
+			c.SetRequest(req.WithContext(logging.NewContext(req.Context(), rl)))
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			res := c.Response()
+			args := []interface{}{
+				"method", req.Method,
+				"route", c.Path(),
+				"status", res.Status,
+				"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+				"bytes", res.Size,
+				"remote_ip", c.RealIP(),
+			}
+			if err != nil {
+				args = append(args, "error", err.Error())
+			}
+			if res.Status >= http.StatusInternalServerError {
+				rl.Error("request", args...)
+			} else {
+				rl.Info("request", args...)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,211 @@
+//go:build unit
+
+package echo_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/internal/http/echo"
+	"github.com/PicPay/go-test-workshop/internal/logging"
+	"github.com/PicPay/go-test-workshop/internal/metrics"
+	"github.com/PicPay/go-test-workshop/internal/tracing"
+	"github.com/PicPay/go-test-workshop/person"
+	person_mock "github.com/PicPay/go-test-workshop/person/mocks"
+	"github.com/PicPay/go-test-workshop/weather"
+	weather_mock "github.com/PicPay/go-test-workshop/weather/mocks"
+	labstack "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+//entry é uma linha registrada pelo recorder, com os pares de chave e valor em fields
+type entry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+//recorder é um logging.Logger que guarda as linhas em memória
+type recorder struct {
+	mu      sync.Mutex
+	entries []entry
+}
+
+func (r *recorder) Info(msg string, args ...interface{})  { r.log("info", msg, args) }
+func (r *recorder) Warn(msg string, args ...interface{})  { r.log("warn", msg, args) }
+func (r *recorder) Error(msg string, args ...interface{}) { r.log("error", msg, args) }
+
+func (r *recorder) log(level, msg string, args []interface{}) {
+	fields := make(map[string]interface{})
+	for i := 0; i+1 < len(args); i += 2 {
+		fields[args[i].(string)] = args[i+1]
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry{level: level, msg: msg, fields: fields})
+}
+
+func (r *recorder) find(t *testing.T, msg string) entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entries {
+		if e.msg == msg {
+			return e
+		}
+	}
+	t.Fatalf("log %q not found", msg)
+	return entry{}
+}
+
+func TestAccessLog(t *testing.T) {
+	newServer := func() (*labstack.Echo, *recorder) {
+		l := &recorder{}
+		e := labstack.New()
+		e.Use(echo.RequestID(), echo.AccessLog(l))
+		e.GET("/people/:id", func(c labstack.Context) error {
+			logging.FromContext(c.Request().Context()).Info("searching person", "id", c.Param("id"))
+			return c.String(http.StatusOK, "ok")
+		})
+		e.GET("/fail", func(c labstack.Context) error {
+			return errors.New("boom")
+		})
+		return e, l
+	}
+	t.Run("registra a requisição", func(t *testing.T) {
+		e, l := newServer()
+		req := httptest.NewRequest(http.MethodGet, "/people/1", nil)
+		req.Header.Set(labstack.HeaderXRealIP, "10.0.0.1")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		access := l.find(t, "request")
+		assert.Equal(t, "info", access.level)
+		assert.Equal(t, http.MethodGet, access.fields["method"])
+		assert.Equal(t, "/people/:id", access.fields["route"])
+		assert.Equal(t, http.StatusOK, access.fields["status"])
+		assert.Equal(t, int64(2), access.fields["bytes"])
+		assert.Equal(t, "10.0.0.1", access.fields["remote_ip"])
+		assert.Contains(t, access.fields, "latency_ms")
+		id := rec.Header().Get(echo.HeaderRequestID)
+		assert.Regexp(t, regexp.MustCompile("^[0-9a-f]{32}$"), id)
+		assert.Equal(t, id, access.fields["request_id"])
+		assert.Equal(t, id, l.find(t, "searching person").fields["request_id"])
+	})
+	t.Run("propaga o id recebido", func(t *testing.T) {
+		e, l := newServer()
+		req := httptest.NewRequest(http.MethodGet, "/people/1", nil)
+		req.Header.Set(echo.HeaderRequestID, "f3b2c1a0-7e6d-4c5b-9a8f-1e2d3c4b5a69")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, "f3b2c1a0-7e6d-4c5b-9a8f-1e2d3c4b5a69", rec.Header().Get(echo.HeaderRequestID))
+		assert.Equal(t, "f3b2c1a0-7e6d-4c5b-9a8f-1e2d3c4b5a69", l.find(t, "searching person").fields["request_id"])
+	})
+	t.Run("substitui o id inválido", func(t *testing.T) {
+		for _, id := range []string{"abc def", "abc\nlevel=error", strings.Repeat("a", 129)} {
+			e, _ := newServer()
+			req := httptest.NewRequest(http.MethodGet, "/people/1", nil)
+			req.Header.Set(echo.HeaderRequestID, id)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Regexp(t, regexp.MustCompile("^[0-9a-f]{32}$"), rec.Header().Get(echo.HeaderRequestID))
+		}
+	})
+	t.Run("erro", func(t *testing.T) {
+		e, l := newServer()
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		access := l.find(t, "request")
+		assert.Equal(t, "error", access.level)
+		assert.Equal(t, http.StatusInternalServerError, access.fields["status"])
+		assert.Equal(t, "boom", access.fields["error"])
+	})
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	l := &recorder{}
+	exporter := tracetest.NewInMemoryExporter()
+	tr := tracing.New(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	m := metrics.New()
+	e := labstack.New()
+	//mesma ordem do cmd/api: os middlewares de Handlers e, por dentro deles, os de tracing e de métricas
+	e.Use(echo.RequestID(), echo.AccessLog(l))
+	e.Use(tr.Echo(), m.Echo())
+	e.GET("/fail", func(c labstack.Context) error {
+		return errors.New("boom")
+	})
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	access := l.find(t, "request")
+	assert.Equal(t, "error", access.level)
+	assert.Equal(t, http.StatusInternalServerError, access.fields["status"])
+	assert.Equal(t, "boom", access.fields["error"])
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "GET /fail", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+
+	scrape := httptest.NewRecorder()
+	m.Handler().ServeHTTP(scrape, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, scrape.Body.String(), `http_requests_total{method="GET",route="/fail",status="500"} 1`)
+}
+
+func TestHandlersRequestID(t *testing.T) {
+	t.Run("erro inesperado é registrado com o id da requisição", func(t *testing.T) {
+		l := &recorder{}
+		e := labstack.New()
+		e.Use(echo.RequestID(), echo.AccessLog(l))
+		s := person_mock.NewUseCase(t)
+		s.On("List", mock.Anything, person.ListOptions{}).Return(nil, errors.New("database is locked")).Once()
+		e.GET("/people", echo.ListPeople(s))
+		req := httptest.NewRequest(http.MethodGet, "/people", nil)
+		req.Header.Set(echo.HeaderRequestID, "req-1")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		unexpected := l.find(t, "unexpected error")
+		assert.Equal(t, "error", unexpected.level)
+		assert.Equal(t, "req-1", unexpected.fields["request_id"])
+		assert.Equal(t, "database is locked", unexpected.fields["error"])
+	})
+	t.Run("falha do provedor é registrada sem expor o detalhe", func(t *testing.T) {
+		l := &recorder{}
+		e := labstack.New()
+		e.Use(echo.RequestID(), echo.AccessLog(l))
+		s := weather_mock.NewUseCase(t)
+		s.On("Get", mock.Anything, weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{}).
+			Return(nil, &weather.UpstreamError{Kind: weather.ErrUnauthorized, StatusCode: 401, Message: "Invalid API key"}).
+			Once()
+		e.GET("/weather/:lat/:long", echo.Weather(s))
+		req := httptest.NewRequest(http.MethodGet, "/weather/-27.5969/-48.5495", nil)
+		req.Header.Set(echo.HeaderRequestID, "req-2")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "Invalid API key")
+		upstream := l.find(t, "weather provider error")
+		assert.Equal(t, "error", upstream.level)
+		assert.Equal(t, "req-2", upstream.fields["request_id"])
+		assert.Contains(t, upstream.fields["error"], "Invalid API key")
+	})
+	t.Run("Handlers devolve o cabeçalho mesmo sem logger", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		echo.Handlers(nil, nil, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hello", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get(echo.HeaderRequestID))
+	})
+}
@@ -0,0 +1,182 @@
+package echo
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/labstack/echo/v4"
+)
+
+//personRequest representa o corpo aceito pelos endpoints de escrita de /people.
+//Os campos são ponteiros para que o PATCH consiga diferenciar um campo ausente de um campo vazio
+type personRequest struct {
+	Name     *string `json:"name"`
+	LastName *string `json:"last_name"`
+}
+
+//ListPeople lista as pessoas de forma paginada. Aceita os parâmetros q, limit, offset, sort (id, name ou last_name) e order (asc ou desc)
+func ListPeople(s person.UseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		opts, err := listOptions(c)
+		if err != nil {
+			return httpError(c, err)
+		}
+		var page *person.Page
+		if q := c.QueryParam("q"); q != "" {
+			page, err = s.Search(ctx, q, opts)
+		} else {
+			page, err = s.List(ctx, opts)
+		}
+		if err != nil {
+			return httpError(c, err)
+		}
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+func GetPerson(s person.UseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := personID(c)
+		if err != nil {
+			return jsonError(c, http.StatusBadRequest, "bad_request", err.Error())
+		}
+		p, err := s.Get(ctx, id)
+		if err != nil {
+			return httpError(c, err)
+		}
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+func CreatePerson(s person.UseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req personRequest
+		if err := c.Bind(&req); err != nil {
+			return jsonError(c, http.StatusBadRequest, "bad_request", "invalid body")
+		}
+		p := &person.Person{}
+		req.apply(p)
+		id, err := s.Create(ctx, p.Name, p.LastName)
+		if err != nil {
+			return httpError(c, err)
+		}
+		p.ID = id
+		c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/people/%d", id))
+		return c.JSON(http.StatusCreated, p)
+	}
+}
+
+//UpdatePerson substitui todos os campos da pessoa (PUT)
+func UpdatePerson(s person.UseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := personID(c)
+		if err != nil {
+			return jsonError(c, http.StatusBadRequest, "bad_request", err.Error())
+		}
+		var req personRequest
+		if err := c.Bind(&req); err != nil {
+			return jsonError(c, http.StatusBadRequest, "bad_request", "invalid body")
+		}
+		p, err := s.Get(ctx, id)
+		if err != nil {
+			return httpError(c, err)
+		}
+		p.Name, p.LastName = "", ""
+		req.apply(p)
+		err = s.Update(ctx, p)
+		if err != nil {
+			return httpError(c, err)
+		}
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+//PatchPerson altera apenas os campos informados no corpo (PATCH)
+func PatchPerson(s person.UseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := personID(c)
+		if err != nil {
+			return jsonError(c, http.StatusBadRequest, "bad_request", err.Error())
+		}
+		var req personRequest
+		if err := c.Bind(&req); err != nil {
+			return jsonError(c, http.StatusBadRequest, "bad_request", "invalid body")
+		}
+		p, err := s.Get(ctx, id)
+		if err != nil {
+			return httpError(c, err)
+		}
+		req.apply(p)
+		err = s.Update(ctx, p)
+		if err != nil {
+			return httpError(c, err)
+		}
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+func DeletePerson(s person.UseCase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := personID(c)
+		if err != nil {
+			return jsonError(c, http.StatusBadRequest, "bad_request", err.Error())
+		}
+		err = s.Delete(ctx, id)
+		if err != nil {
+			return httpError(c, err)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+//apply copia para p os campos informados na requisição. A validação fica a cargo do person.UseCase
+func (r personRequest) apply(p *person.Person) {
+	if r.Name != nil {
+		p.Name = *r.Name
+	}
+	if r.LastName != nil {
+		p.LastName = *r.LastName
+	}
+}
+
+func listOptions(c echo.Context) (person.ListOptions, error) {
+	var opts person.ListOptions
+	var err error
+	if v := c.QueryParam("limit"); v != "" {
+		opts.Limit, err = strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("%w: invalid limit %q", person.ErrInvalidListOptions, v)
+		}
+	}
+	if v := c.QueryParam("offset"); v != "" {
+		opts.Offset, err = strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("%w: invalid offset %q", person.ErrInvalidListOptions, v)
+		}
+	}
+	opts.SortBy = person.SortField(c.QueryParam("sort"))
+	switch c.QueryParam("order") {
+	case "", "asc":
+	case "desc":
+		opts.Desc = true
+	default:
+		return opts, fmt.Errorf("%w: invalid order %q", person.ErrInvalidListOptions, c.QueryParam("order"))
+	}
+	return opts, nil
+}
+
+func personID(c echo.Context) (person.ID, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q", c.Param("id"))
+	}
+	return person.ID(id), nil
+}
@@ -0,0 +1,257 @@
+//go:build unit
+
+package echo_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/internal/http/echo"
+	"github.com/PicPay/go-test-workshop/person"
+	person_mock "github.com/PicPay/go-test-workshop/person/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListPeople(t *testing.T) {
+	t.Run("lista todas as pessoas", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/people", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("List", mock.Anything, person.ListOptions{}).
+			Return(&person.Page{
+				People: []*person.Person{{ID: 1, Name: "Ronnie", LastName: "Dio"}},
+				Total:  1,
+				Limit:  person.DefaultLimit,
+			}, nil).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		h := echo.ListPeople(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"people":[{"id":1,"name":"Ronnie","last_name":"Dio"}],"total":1,"limit":20,"offset":0}`, rec.Body.String())
+	})
+	t.Run("paginação e ordenação", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/people?limit=1&offset=2&sort=last_name&order=desc", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("List", mock.Anything, person.ListOptions{Limit: 1, Offset: 2, SortBy: person.SortByLastName, Desc: true}).
+			Return(&person.Page{
+				People: []*person.Person{{ID: 1, Name: "Ronnie", LastName: "Dio"}},
+				Total:  3,
+				Limit:  1,
+				Offset: 2,
+			}, nil).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		h := echo.ListPeople(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"people":[{"id":1,"name":"Ronnie","last_name":"Dio"}],"total":3,"limit":1,"offset":2}`, rec.Body.String())
+	})
+	t.Run("paginação inválida", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/people?limit=abacate", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		h := echo.ListPeople(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("busca por q sem resultados", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/people?q=dio", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("Search", mock.Anything, "dio", person.ListOptions{}).
+			Return(&person.Page{People: []*person.Person{}, Limit: person.DefaultLimit}, nil).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		h := echo.ListPeople(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"people":[],"total":0,"limit":20,"offset":0}`, rec.Body.String())
+	})
+}
+
+func TestGetPerson(t *testing.T) {
+	t.Run("status ok", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("Get", mock.Anything, person.ID(1)).
+			Return(&person.Person{ID: 1, Name: "Ronnie", LastName: "Dio"}, nil).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/people/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		h := echo.GetPerson(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id":1,"name":"Ronnie","last_name":"Dio"}`, rec.Body.String())
+	})
+	t.Run("status not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("Get", mock.Anything, person.ID(1)).
+			Return(nil, fmt.Errorf("erro lendo person do repositório: %w", person.ErrNotFound)).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/people/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		h := echo.GetPerson(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("id inválido", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/people/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("abacate")
+		h := echo.GetPerson(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestCreatePerson(t *testing.T) {
+	t.Run("status created", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/people", strings.NewReader(`{"name":"Ronnie","last_name":"Dio"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("Create", mock.Anything, "Ronnie", "Dio").
+			Return(person.ID(10), nil).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		h := echo.CreatePerson(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "/people/10", rec.Header().Get("Location"))
+		assert.JSONEq(t, `{"id":10,"name":"Ronnie","last_name":"Dio"}`, rec.Body.String())
+	})
+	t.Run("status unprocessable entity", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/people", strings.NewReader(`{"name":"Ronnie"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("Create", mock.Anything, "Ronnie", "").
+			Return(person.ID(0), fmt.Errorf("%w: last name is required", person.ErrInvalidPerson)).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		h := echo.CreatePerson(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"error":"invalid_person","message":"invalid person: last name is required"}`, rec.Body.String())
+	})
+	t.Run("status conflict", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/people", strings.NewReader(`{"name":"Ronnie","last_name":"Dio"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("Create", mock.Anything, "Ronnie", "Dio").
+			Return(person.ID(0), fmt.Errorf("erro criando person no repositório: %w", person.ErrConflict)).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		h := echo.CreatePerson(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
+
+func TestUpdatePerson(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"name":"Tony","last_name":"Iommi"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	s := person_mock.NewUseCase(t)
+	s.On("Get", mock.Anything, person.ID(1)).
+		Return(&person.Person{ID: 1, Name: "Ronnie", LastName: "Dio"}, nil).
+		Once()
+	s.On("Update", mock.Anything, &person.Person{ID: 1, Name: "Tony", LastName: "Iommi"}).
+		Return(nil).
+		Once()
+	c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+	c.SetPath("/people/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	h := echo.UpdatePerson(s)
+	err := h(c)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":1,"name":"Tony","last_name":"Iommi"}`, rec.Body.String())
+}
+
+func TestPatchPerson(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"last_name":"James Dio"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	s := person_mock.NewUseCase(t)
+	s.On("Get", mock.Anything, person.ID(1)).
+		Return(&person.Person{ID: 1, Name: "Ronnie", LastName: "Dio"}, nil).
+		Once()
+	s.On("Update", mock.Anything, &person.Person{ID: 1, Name: "Ronnie", LastName: "James Dio"}).
+		Return(nil).
+		Once()
+	c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+	c.SetPath("/people/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	h := echo.PatchPerson(s)
+	err := h(c)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":1,"name":"Ronnie","last_name":"James Dio"}`, rec.Body.String())
+}
+
+func TestDeletePerson(t *testing.T) {
+	t.Run("status no content", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("Delete", mock.Anything, person.ID(1)).
+			Return(nil).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/people/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		h := echo.DeletePerson(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("status not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		s := person_mock.NewUseCase(t)
+		s.On("Delete", mock.Anything, person.ID(1)).
+			Return(fmt.Errorf("erro removendo person do repositório: %w", person.ErrNotFound)).
+			Once()
+		c := echo.Handlers(nil, nil, nil).NewContext(req, rec)
+		c.SetPath("/people/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		h := echo.DeletePerson(s)
+		err := h(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
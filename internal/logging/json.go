@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+//jsonLogger escreve uma linha em JSON por mensagem, com time, level, msg e os pares de chave e valor
+type jsonLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+//New retorna um Logger que escreve em w uma linha em JSON por mensagem, como
+//{"level":"info","msg":"request","request_id":"abc","status":200,"time":"2022-06-21T15:34:16Z"}.
+//Erros e valores com o método String são escritos como texto
+func New(w io.Writer) Logger {
+	return &jsonLogger{w: w}
+}
+
+func (l *jsonLogger) Info(msg string, args ...interface{}) {
+	l.write("info", msg, args)
+}
+
+func (l *jsonLogger) Warn(msg string, args ...interface{}) {
+	l.write("warn", msg, args)
+}
+
+func (l *jsonLogger) Error(msg string, args ...interface{}) {
+	l.write("error", msg, args)
+}
+
+func (l *jsonLogger) write(level, msg string, args []interface{}) {
+	line := map[string]interface{}{
+		"time":  time.Now().UTC().Format(time.RFC3339Nano),
+		"level": level,
+		"msg":   msg,
+	}
+	for i := 0; i < len(args); i += 2 {
+		key := fmt.Sprint(args[i])
+		if i+1 == len(args) {
+			line["!extra"] = value(args[i])
+			break
+		}
+		line[key] = value(args[i+1])
+	}
+	b, err := json.Marshal(line)
+	if err != nil {
+		b, _ = json.Marshal(map[string]interface{}{"time": line["time"], "level": level, "msg": msg, "log_error": err.Error()})
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(append(b, '\n'))
+}
+
+//value converte os valores que o encoding/json não escreve como texto, como os erros, que viram {}
+func value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
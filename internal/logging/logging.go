@@ -0,0 +1,87 @@
+/*
+Package logging leva o logger e o id da requisição pelo context.Context.
+
+O middleware do Echo cria, para cada requisição, um logger com o campo request_id e o coloca no
+contexto da requisição. Os serviços recebem esse contexto nos métodos das suas interfaces, então
+basta usar FromContext para que as linhas de log de uma requisição possam ser encontradas pelo id.
+*/
+package logging
+
+import "context"
+
+//Logger é o logger estruturado usado pelos handlers e serviços, implementado por New.
+//Os argumentos depois da mensagem são pares de chave e valor, como "status", 200
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+)
+
+//With retorna um Logger que adiciona args a todas as linhas de log de l
+func With(l Logger, args ...interface{}) Logger {
+	if len(args) == 0 {
+		return l
+	}
+	return &fields{next: l, args: args}
+}
+
+type fields struct {
+	next Logger
+	args []interface{}
+}
+
+func (f *fields) Info(msg string, args ...interface{}) {
+	f.next.Info(msg, f.with(args)...)
+}
+
+func (f *fields) Warn(msg string, args ...interface{}) {
+	f.next.Warn(msg, f.with(args)...)
+}
+
+func (f *fields) Error(msg string, args ...interface{}) {
+	f.next.Error(msg, f.with(args)...)
+}
+
+//with cria um slice novo para que chamadas concorrentes não compartilhem o array de f.args
+func (f *fields) with(args []interface{}) []interface{} {
+	all := make([]interface{}, 0, len(f.args)+len(args))
+	return append(append(all, f.args...), args...)
+}
+
+//NewContext retorna uma cópia de ctx com o logger l
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+//FromContext retorna o logger de ctx. Se não houver nenhum, retorna um logger que descarta as mensagens,
+//então o código chamado fora de uma requisição, como nos testes, não precisa verificar
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey).(Logger); ok {
+		return l
+	}
+	return nop{}
+}
+
+type nop struct{}
+
+func (nop) Info(string, ...interface{})  {}
+func (nop) Warn(string, ...interface{})  {}
+func (nop) Error(string, ...interface{}) {}
+
+//WithRequestID retorna uma cópia de ctx com o id da requisição
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+//RequestID retorna o id da requisição guardado em ctx, ou vazio
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
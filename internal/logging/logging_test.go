@@ -0,0 +1,81 @@
+//go:build unit
+
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+//lines é um logging.Logger que guarda os argumentos de cada chamada
+type lines [][]interface{}
+
+func (l *lines) Info(msg string, args ...interface{})  { l.add(msg, args) }
+func (l *lines) Warn(msg string, args ...interface{})  { l.add(msg, args) }
+func (l *lines) Error(msg string, args ...interface{}) { l.add(msg, args) }
+
+func (l *lines) add(msg string, args []interface{}) {
+	*l = append(*l, append([]interface{}{msg}, args...))
+}
+
+func TestWith(t *testing.T) {
+	var out lines
+	l := logging.With(&out, "request_id", "abc")
+	l.Info("request", "status", 200)
+	l.Error("unexpected error")
+	logging.With(l, "provider", 1).Warn("fallback")
+	assert.Equal(t, lines{
+		{"request", "request_id", "abc", "status", 200},
+		{"unexpected error", "request_id", "abc"},
+		{"fallback", "request_id", "abc", "provider", 1},
+	}, out)
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("sem logger no contexto", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			logging.FromContext(context.Background()).Error("discarded")
+		})
+	})
+	t.Run("com logger no contexto", func(t *testing.T) {
+		var out lines
+		ctx := logging.NewContext(context.Background(), &out)
+		logging.FromContext(ctx).Info("hello")
+		assert.Equal(t, lines{{"hello"}}, out)
+	})
+	t.Run("request id", func(t *testing.T) {
+		assert.Empty(t, logging.RequestID(context.Background()))
+		assert.Equal(t, "abc", logging.RequestID(logging.WithRequestID(context.Background(), "abc")))
+	})
+}
+
+func TestNew(t *testing.T) {
+	var out bytes.Buffer
+	l := logging.With(logging.New(&out), "request_id", "abc")
+	l.Info("request", "status", 200, "latency_ms", 1.5)
+	l.Warn("weather provider failed, trying the next one", "provider", "openweather", "error", errors.New("timeout"))
+	l.Error("odd", "key")
+
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		var m map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &m), line)
+		_, err := time.Parse(time.RFC3339Nano, m["time"].(string))
+		assert.Nil(t, err)
+		delete(m, "time")
+		lines = append(lines, m)
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "info", "msg": "request", "request_id": "abc", "status": float64(200), "latency_ms": 1.5},
+		{"level": "warn", "msg": "weather provider failed, trying the next one", "request_id": "abc", "provider": "openweather", "error": "timeout"},
+		{"level": "error", "msg": "odd", "request_id": "abc", "!extra": "key"},
+	}, lines)
+}
@@ -0,0 +1,123 @@
+/*
+Package metrics expõe as métricas da API no formato do Prometheus.
+
+As métricas são coletadas por decorators em volta das interfaces dos pacotes de domínio, como
+person.Repository e weather.HTTPClient, e por um middleware do Echo, então esses pacotes não
+dependem do Prometheus.
+*/
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+//Metrics guarda os coletores registrados em um registry próprio, então várias instâncias podem ser criadas nos testes
+type Metrics struct {
+	registry        *prometheus.Registry
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	repoDuration    *prometheus.HistogramVec
+	weatherRequests *prometheus.CounterVec
+	weatherDuration *prometheus.HistogramVec
+}
+
+//New cria as métricas, incluindo as do runtime do Go e do processo
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Requisições HTTP atendidas, por método, rota e status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duração das requisições HTTP, por método e rota.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		repoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "person_repository_duration_seconds",
+			Help:    "Duração das chamadas ao person.Repository, por método e resultado.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "outcome"}),
+		weatherRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "weather_upstream_requests_total",
+			Help: "Chamadas aos provedores de previsão do tempo, por provedor e resultado.",
+		}, []string{"provider", "outcome"}),
+		weatherDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "weather_upstream_duration_seconds",
+			Help:    "Duração das chamadas aos provedores de previsão do tempo, incluindo as novas tentativas.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"provider"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.repoDuration,
+		m.weatherRequests,
+		m.weatherDuration,
+	)
+	return m
+}
+
+//Handler responde com as métricas no formato texto do Prometheus, para ser servido em /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+//RegisterDB adiciona as estatísticas do pool de conexões de db, como conexões abertas, em uso e esperas
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+//Echo retorna o middleware que conta as requisições e mede a sua duração. A rota é o caminho registrado,
+//como /people/:id, para que os ids não criem uma série para cada valor. Um erro do handler é escrito na resposta com
+//c.Error antes da contagem, para que o status seja o final, e depois devolvido aos middlewares registrados antes
+func (m *Metrics) Echo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			method := c.Request().Method
+			status := c.Response().Status
+			route := c.Path()
+			if status == http.StatusNotFound && !registered(c.Echo(), route) {
+				route = "unmatched"
+			}
+			m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+			m.httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
+
+//registered verifica se path é uma rota registrada. Requisições sem rota recebem o próprio caminho em c.Path(),
+//e usar esse valor no label criaria uma série para cada URL inexistente
+func registered(e *echo.Echo, path string) bool {
+	for _, r := range e.Routes() {
+		if r.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+//outcome retorna o resultado usado nos labels: ok ou error
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,128 @@
+//go:build unit
+
+package metrics_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/internal/metrics"
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/PicPay/go-test-workshop/person/inmem"
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/PicPay/go-test-workshop/weather/mocks"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+//scrape retorna o conteúdo de /metrics
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestEcho(t *testing.T) {
+	m := metrics.New()
+	e := echo.New()
+	e.Use(m.Echo())
+	e.GET("/people/:id", func(c echo.Context) error {
+		if c.Param("id") == "0" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return c.String(http.StatusOK, "ok")
+	})
+	for _, path := range []string{"/people/1", "/people/2", "/people/0", "/unknown"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	body := scrape(t, m)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/people/:id",status="200"} 2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/people/:id",status="404"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/people/:id"} 3`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	m := metrics.New()
+	repo := m.Repository(inmem.NewInMem())
+	id, err := repo.Create(ctx, &person.Person{Name: "Ronnie", LastName: "Dio"})
+	assert.Nil(t, err)
+	_, err = repo.Get(ctx, id)
+	assert.Nil(t, err)
+	_, err = repo.Get(ctx, id+100)
+	assert.ErrorIs(t, err, person.ErrNotFound)
+	body := scrape(t, m)
+	assert.Contains(t, body, `person_repository_duration_seconds_count{method="Create",outcome="ok"} 1`)
+	assert.Contains(t, body, `person_repository_duration_seconds_count{method="Get",outcome="ok"} 1`)
+	assert.Contains(t, body, `person_repository_duration_seconds_count{method="Get",outcome="not_found"} 1`)
+}
+
+func TestHTTPClient(t *testing.T) {
+	response := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Body: ioutil.NopCloser(strings.NewReader(`{}`))}
+	}
+	next := mocks.NewHTTPClient(t)
+	next.On("Do", mock.Anything).Return(response(http.StatusOK), nil).Twice()
+	next.On("Do", mock.Anything).Return(response(http.StatusTooManyRequests), nil).Once()
+	next.On("Do", mock.Anything).Return(response(http.StatusServiceUnavailable), nil).Once()
+	next.On("Do", mock.Anything).Return(response(http.StatusUnauthorized), nil).Once()
+	next.On("Do", mock.Anything).Return(nil, weather.ErrCircuitOpen).Once()
+	next.On("Do", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+	m := metrics.New()
+	client := m.HTTPClient(next, "openweather")
+	req := httptest.NewRequest(http.MethodGet, "https://api.openweathermap.org/data/2.5/weather", nil)
+	for i := 0; i < 7; i++ {
+		client.Do(req)
+	}
+	body := scrape(t, m)
+	for outcome, count := range map[string]string{
+		"ok":           "2",
+		"rate_limited": "1",
+		"server_error": "1",
+		"client_error": "1",
+		"circuit_open": "1",
+		"error":        "1",
+	} {
+		assert.Contains(t, body, `weather_upstream_requests_total{outcome="`+outcome+`",provider="openweather"} `+count)
+	}
+	assert.Contains(t, body, `weather_upstream_duration_seconds_count{provider="openweather"} 7`)
+}
+
+func TestRegisterDB(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(3)
+	m := metrics.New()
+	m.RegisterDB(db, "workshop")
+	assert.Contains(t, scrape(t, m), `go_sql_max_open_connections{db_name="workshop"} 3`)
+}
+
+func TestRegisterCache(t *testing.T) {
+	ctx := context.Background()
+	coord := weather.Coord{Lat: -27.6, Lon: -48.55}
+	next := mocks.NewUseCase(t)
+	next.On("Get", ctx, coord, weather.Options{}).Return(&weather.Weather{Name: "Florianópolis"}, nil).Once()
+	c := weather.NewCache(next)
+	m := metrics.New()
+	m.RegisterCache(c)
+	for i := 0; i < 3; i++ {
+		_, err := c.Get(ctx, coord, weather.Options{})
+		assert.Nil(t, err)
+	}
+	body := scrape(t, m)
+	assert.Contains(t, body, "weather_cache_hits_total 2")
+	assert.Contains(t, body, "weather_cache_misses_total 1")
+	assert.Contains(t, body, "weather_cache_entries 1")
+}
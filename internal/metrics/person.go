@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/person"
+)
+
+//repository é um person.Repository que mede a duração de cada chamada a next
+type repository struct {
+	next    person.Repository
+	metrics *Metrics
+}
+
+//Repository envolve next para medir a duração das chamadas por método
+func (m *Metrics) Repository(next person.Repository) person.Repository {
+	return &repository{next: next, metrics: m}
+}
+
+//observe registra a duração desde start. ErrNotFound e ErrConflict são resultados esperados e não contam como erro
+func (r *repository) observe(method string, start time.Time, err error) {
+	o := outcome(err)
+	switch {
+	case errors.Is(err, person.ErrNotFound):
+		o = "not_found"
+	case errors.Is(err, person.ErrConflict):
+		o = "conflict"
+	}
+	r.metrics.repoDuration.WithLabelValues(method, o).Observe(time.Since(start).Seconds())
+}
+
+func (r *repository) Get(ctx context.Context, id person.ID) (*person.Person, error) {
+	start := time.Now()
+	p, err := r.next.Get(ctx, id)
+	r.observe("Get", start, err)
+	return p, err
+}
+
+func (r *repository) Search(ctx context.Context, query string, opts person.ListOptions) (*person.Page, error) {
+	start := time.Now()
+	page, err := r.next.Search(ctx, query, opts)
+	r.observe("Search", start, err)
+	return page, err
+}
+
+func (r *repository) List(ctx context.Context, opts person.ListOptions) (*person.Page, error) {
+	start := time.Now()
+	page, err := r.next.List(ctx, opts)
+	r.observe("List", start, err)
+	return page, err
+}
+
+func (r *repository) Create(ctx context.Context, e *person.Person) (person.ID, error) {
+	start := time.Now()
+	id, err := r.next.Create(ctx, e)
+	r.observe("Create", start, err)
+	return id, err
+}
+
+func (r *repository) Update(ctx context.Context, e *person.Person) error {
+	start := time.Now()
+	err := r.next.Update(ctx, e)
+	r.observe("Update", start, err)
+	return err
+}
+
+func (r *repository) Delete(ctx context.Context, id person.ID) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	r.observe("Delete", start, err)
+	return err
+}
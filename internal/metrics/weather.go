@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//httpClient é um weather.HTTPClient que conta as chamadas ao provedor por resultado
+type httpClient struct {
+	next     weather.HTTPClient
+	provider string
+	metrics  *Metrics
+}
+
+//HTTPClient envolve o cliente HTTP do provedor. Deve ficar por fora do circuit breaker e das novas tentativas,
+//para que cada chamada seja contada uma vez e as recusas com o circuito aberto apareçam como circuit_open
+func (m *Metrics) HTTPClient(next weather.HTTPClient, provider string) weather.HTTPClient {
+	return &httpClient{next: next, provider: provider, metrics: m}
+}
+
+//RegisterCache expõe os contadores de c.Stats, lidos a cada coleta do /metrics
+func (m *Metrics) RegisterCache(c *weather.Cache) {
+	m.registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "weather_cache_hits_total",
+			Help: "Consultas de previsão do tempo respondidas pelo cache.",
+		}, func() float64 { return float64(c.Stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "weather_cache_misses_total",
+			Help: "Consultas de previsão do tempo que não estavam no cache ou estavam expiradas.",
+		}, func() float64 { return float64(c.Stats().Misses) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "weather_cache_entries",
+			Help: "Respostas guardadas no cache de previsão do tempo.",
+		}, func() float64 { return float64(c.Stats().Entries) }),
+	)
+}
+
+func (c *httpClient) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.next.Do(req)
+	c.metrics.weatherDuration.WithLabelValues(c.provider).Observe(time.Since(start).Seconds())
+	c.metrics.weatherRequests.WithLabelValues(c.provider, upstreamOutcome(resp, err)).Inc()
+	return resp, err
+}
+
+//upstreamOutcome classifica a chamada: ok, client_error, rate_limited, server_error, circuit_open ou error
+//para falhas de conexão e timeouts
+func upstreamOutcome(resp *http.Response, err error) string {
+	switch {
+	case errors.Is(err, weather.ErrCircuitOpen):
+		return "circuit_open"
+	case err != nil:
+		return "error"
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "rate_limited"
+	case resp.StatusCode >= 500:
+		return "server_error"
+	case resp.StatusCode >= 400:
+		return "client_error"
+	default:
+		return "ok"
+	}
+}
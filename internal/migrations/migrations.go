@@ -0,0 +1,339 @@
+/*
+Package migrations versiona o schema do banco de dados.
+
+Os arquivos SQL ficam embarcados no binário, em um diretório por dialeto, com o formato
+<versão>_<nome>.up.sql e <versão>_<nome>.down.sql. As versões aplicadas são registradas na
+tabela schema_migrations junto com o checksum do arquivo up, permitindo detectar migrações
+que foram alteradas depois de aplicadas.
+*/
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var embedded embed.FS
+
+//Dialect identifica o banco de dados, e consequentemente o diretório de migrações usado
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+var (
+	//ErrChecksumMismatch é retornado quando uma migração aplicada foi alterada depois de aplicada
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	//ErrUnknownVersion é retornado quando o banco possui uma versão que não existe nos arquivos de migração
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+//Migration é uma versão do schema
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	fsys       fs.FS
+	migrations []Migration
+}
+
+type Option func(*Migrator)
+
+//WithFS substitui os arquivos embarcados. Os arquivos devem estar em um diretório com o nome do dialeto
+func WithFS(fsys fs.FS) Option {
+	return func(m *Migrator) {
+		m.fsys = fsys
+	}
+}
+
+func New(db *sql.DB, dialect Dialect, options ...Option) (*Migrator, error) {
+	m := &Migrator{
+		db:      db,
+		dialect: dialect,
+		fsys:    embedded,
+	}
+	for _, o := range options {
+		o(m)
+	}
+	var err error
+	m.migrations, err = load(m.fsys, string(dialect))
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+//Migrations retorna as migrações conhecidas, ordenadas por versão
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+//Version retorna a última versão aplicada, ou zero se nenhuma migração foi aplicada
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	err := m.createVersionTable(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var version sql.NullInt64
+	err = m.db.QueryRowContext(ctx, "select max(version) from schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+//Up verifica os checksums das migrações já aplicadas e aplica, em ordem, as pendentes
+func (m *Migrator) Up(ctx context.Context) error {
+	applied, err := m.verify(ctx)
+	if err != nil {
+		return err
+	}
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		err = m.apply(ctx, mig.Up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx,
+				fmt.Sprintf("insert into schema_migrations (version, name, checksum) values (%s, %s, %s)", m.placeholder(1), m.placeholder(2), m.placeholder(3)),
+				mig.Version, mig.Name, mig.Checksum)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("applying migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+	}
+	return nil
+}
+
+//Down reverte a última migração aplicada. Não faz nada se nenhuma migração foi aplicada
+func (m *Migrator) Down(ctx context.Context) error {
+	applied, err := m.verify(ctx)
+	if err != nil {
+		return err
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		err = m.apply(ctx, mig.Down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "delete from schema_migrations where version = "+m.placeholder(1), mig.Version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("reverting migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+//Seed executa script, como os dados de exemplo usados em desenvolvimento, em uma transação. O script deve estar
+//no dialeto do Migrator e, diferente das migrações, não é versionado e é executado sempre que Seed é chamado,
+//então deve ser idempotente
+func (m *Migrator) Seed(ctx context.Context, script string) error {
+	err := m.apply(ctx, script, func(*sql.Tx) error { return nil })
+	if err != nil {
+		return fmt.Errorf("applying seed: %w", err)
+	}
+	return nil
+}
+
+//verify retorna os checksums das versões aplicadas, falhando se algum não corresponder aos arquivos
+func (m *Migrator) verify(ctx context.Context) (map[int]string, error) {
+	err := m.createVersionTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := m.db.QueryContext(ctx, "select version, checksum from schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var checksum string
+		err = rows.Scan(&version, &checksum)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = checksum
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	known := make(map[int]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = mig
+	}
+	for version, checksum := range applied {
+		mig, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+		if mig.Checksum != checksum {
+			return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, mig.Version, mig.Name)
+		}
+	}
+	return applied, nil
+}
+
+//apply executa o script e o registro da versão na mesma transação.
+//No MySQL os comandos DDL fazem commit implícito, então a transação protege apenas o registro da versão
+func (m *Migrator) apply(ctx context.Context, script string, record func(*sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range statements(script) {
+		_, err = tx.ExecContext(ctx, stmt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	err = record(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) createVersionTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `create table if not exists schema_migrations (
+		version int not null primary key,
+		name varchar(255) not null,
+		checksum varchar(64) not null,
+		applied_at timestamp default current_timestamp
+	)`)
+	return err
+}
+
+func (m *Migrator) placeholder(i int) string {
+	if m.dialect == Postgres {
+		return "$" + strconv.Itoa(i)
+	}
+	return "?"
+}
+
+//load lê e valida os pares up/down do diretório do dialeto
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	files, err := fs.Glob(fsys, dir+"/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no migrations found for dialect %q", dir)
+	}
+	byVersion := make(map[int]*Migration)
+	for _, f := range files {
+		base := path.Base(f)
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("invalid migration file name %q", base)
+		}
+		name := strings.TrimSuffix(base, "."+direction+".sql")
+		parts := strings.SplitN(name, "_", 2)
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %q", base)
+		}
+		content, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = mig
+		}
+		if mig.Name != parts[1] {
+			return nil, fmt.Errorf("migration %d has different names: %q and %q", version, mig.Name, parts[1])
+		}
+		if direction == "up" {
+			mig.Up = string(content)
+			sum := sha256.Sum256(content)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(content)
+		}
+	}
+	var migrations []Migration
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+//statements separa o script em comandos, já que nem todos os drivers aceitam vários comandos em uma única execução.
+//Um ; só encerra o comando fora de strings e identificadores entre aspas ('a;b', "a;b" ou `a;b`), e comentários (--)
+//fora deles são ignorados. Aspas dentro de uma string devem ser dobradas ('it''s'), a forma aceita pelos três bancos
+func statements(script string) []string {
+	var stmts []string
+	var cur strings.Builder
+	var quote rune
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			//uma aspa dobrada fecha e reabre a string, então basta alternar o estado
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			cur.WriteRune('\n')
+			continue
+		case r == ';':
+			if stmt := strings.TrimSpace(cur.String()); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+			cur.Reset()
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	if stmt := strings.TrimSpace(cur.String()); stmt != "" {
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
@@ -0,0 +1,162 @@
+//go:build integration
+
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/PicPay/go-test-workshop/internal/migrations"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+//os testes usam o SQLite por não precisarem de um container
+func openDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "workshop.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	var n int
+	err := db.QueryRow("select count(*) from sqlite_master where type = 'table' and name = ?", table).Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n == 1
+}
+
+func TestUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m, err := migrations.New(db, migrations.SQLite)
+	assert.Nil(t, err)
+	latest := m.Migrations()[len(m.Migrations())-1].Version
+
+	t.Run("banco vazio", func(t *testing.T) {
+		version, err := m.Version(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, version)
+	})
+	t.Run("aplica as migrações", func(t *testing.T) {
+		err := m.Up(ctx)
+		assert.Nil(t, err)
+		version, err := m.Version(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, latest, version)
+		assert.True(t, tableExists(t, db, "person"))
+	})
+	t.Run("up é idempotente", func(t *testing.T) {
+		err := m.Up(ctx)
+		assert.Nil(t, err)
+	})
+	t.Run("reverte as migrações", func(t *testing.T) {
+		for i := 0; i < len(m.Migrations()); i++ {
+			err := m.Down(ctx)
+			assert.Nil(t, err)
+		}
+		version, err := m.Version(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, version)
+		assert.False(t, tableExists(t, db, "person"))
+	})
+}
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m, err := migrations.New(db, migrations.SQLite)
+	assert.Nil(t, err)
+	assert.Nil(t, m.Up(ctx))
+	seed := "insert or ignore into person (id, first_name, last_name) values (1, 'Elton', 'Minetto');"
+	t.Run("insere os dados", func(t *testing.T) {
+		assert.Nil(t, m.Seed(ctx, seed))
+		var name string
+		assert.Nil(t, db.QueryRow("select first_name from person where id = 1").Scan(&name))
+		assert.Equal(t, "Elton", name)
+	})
+	t.Run("pode ser executado novamente", func(t *testing.T) {
+		assert.Nil(t, m.Seed(ctx, seed))
+		var n int
+		assert.Nil(t, db.QueryRow("select count(*) from person").Scan(&n))
+		assert.Equal(t, 1, n)
+	})
+	t.Run("ponto e vírgula e comentários dentro de strings", func(t *testing.T) {
+		script := `-- nomes com separadores
+insert into person (id, first_name, last_name) values (2, 'Ronnie; James', 'Dio -- The Voice');
+insert into person (id, first_name, last_name) values (3, 'Tony', 'O''Iommi;');`
+		assert.Nil(t, m.Seed(ctx, script))
+		var first, last string
+		assert.Nil(t, db.QueryRow("select first_name, last_name from person where id = 2").Scan(&first, &last))
+		assert.Equal(t, "Ronnie; James", first)
+		assert.Equal(t, "Dio -- The Voice", last)
+		assert.Nil(t, db.QueryRow("select last_name from person where id = 3").Scan(&last))
+		assert.Equal(t, "O'Iommi;", last)
+	})
+	t.Run("erro no script", func(t *testing.T) {
+		err := m.Seed(ctx, "insert into band (id) values (1);")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSeedFile(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m, err := migrations.New(db, migrations.SQLite)
+	assert.Nil(t, err)
+	assert.Nil(t, m.Up(ctx))
+	script, err := os.ReadFile("../../ops/db/seed/sqlite.sql")
+	assert.Nil(t, err)
+	assert.Nil(t, m.Seed(ctx, string(script)))
+	assert.Nil(t, m.Seed(ctx, string(script)))
+	var name string
+	assert.Nil(t, db.QueryRow("select last_name from person where id = 1").Scan(&name))
+	assert.Equal(t, "Minetto", name)
+}
+
+func TestChecksum(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	fsys := fstest.MapFS{
+		"sqlite/0001_create_band.up.sql":   {Data: []byte("create table band (id integer primary key);")},
+		"sqlite/0001_create_band.down.sql": {Data: []byte("drop table band;")},
+	}
+	m, err := migrations.New(db, migrations.SQLite, migrations.WithFS(fsys))
+	assert.Nil(t, err)
+	assert.Nil(t, m.Up(ctx))
+
+	fsys["sqlite/0001_create_band.up.sql"] = &fstest.MapFile{Data: []byte("create table band (id integer primary key, name text);")}
+	m, err = migrations.New(db, migrations.SQLite, migrations.WithFS(fsys))
+	assert.Nil(t, err)
+	err = m.Up(ctx)
+	assert.ErrorIs(t, err, migrations.ErrChecksumMismatch)
+}
+
+func TestUnknownVersion(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m, err := migrations.New(db, migrations.SQLite)
+	assert.Nil(t, err)
+	assert.Nil(t, m.Up(ctx))
+	_, err = db.Exec("insert into schema_migrations (version, name, checksum) values (9999, 'future', 'x')")
+	assert.Nil(t, err)
+	err = m.Up(ctx)
+	assert.ErrorIs(t, err, migrations.ErrUnknownVersion)
+}
+
+func TestInvalidFiles(t *testing.T) {
+	db := openDB(t)
+	fsys := fstest.MapFS{
+		"sqlite/0001_create_band.up.sql": {Data: []byte("create table band (id integer primary key);")},
+	}
+	_, err := migrations.New(db, migrations.SQLite, migrations.WithFS(fsys))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1 @@
+create table if not exists person (id serial primary key, first_name varchar(100), last_name varchar(100), created_at timestamp, updated_at timestamp);
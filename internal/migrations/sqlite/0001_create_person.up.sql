@@ -0,0 +1,8 @@
+-- as colunas de nome usam collate nocase para que a ordenação e as comparações não diferenciem maiúsculas, assim como no MySQL
+create table if not exists person (
+	id integer primary key autoincrement,
+	first_name varchar(100) collate nocase,
+	last_name varchar(100) collate nocase,
+	created_at datetime,
+	updated_at datetime
+);
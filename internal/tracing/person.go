@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/PicPay/go-test-workshop/person"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//useCase é um person.UseCase que cria um span para cada chamada a next
+type useCase struct {
+	next   person.UseCase
+	tracer trace.Tracer
+}
+
+//UseCase envolve o serviço de pessoas. Os spans se chamam person.UseCase/<método>
+func (t *Tracing) UseCase(next person.UseCase) person.UseCase {
+	return &useCase{next: next, tracer: t.tracer}
+}
+
+func (u *useCase) Get(ctx context.Context, id person.ID) (*person.Person, error) {
+	ctx, span := u.tracer.Start(ctx, "person.UseCase/Get", trace.WithAttributes(personID(id)))
+	p, err := u.next.Get(ctx, id)
+	end(span, err)
+	return p, err
+}
+
+func (u *useCase) Search(ctx context.Context, query string, opts person.ListOptions) (*person.Page, error) {
+	ctx, span := u.tracer.Start(ctx, "person.UseCase/Search")
+	page, err := u.next.Search(ctx, query, opts)
+	end(span, err)
+	return page, err
+}
+
+func (u *useCase) List(ctx context.Context, opts person.ListOptions) (*person.Page, error) {
+	ctx, span := u.tracer.Start(ctx, "person.UseCase/List")
+	page, err := u.next.List(ctx, opts)
+	end(span, err)
+	return page, err
+}
+
+func (u *useCase) Create(ctx context.Context, firstName, lastName string) (person.ID, error) {
+	ctx, span := u.tracer.Start(ctx, "person.UseCase/Create")
+	id, err := u.next.Create(ctx, firstName, lastName)
+	if err == nil {
+		span.SetAttributes(personID(id))
+	}
+	end(span, err)
+	return id, err
+}
+
+func (u *useCase) Update(ctx context.Context, e *person.Person) error {
+	ctx, span := u.tracer.Start(ctx, "person.UseCase/Update", trace.WithAttributes(personID(e.ID)))
+	err := u.next.Update(ctx, e)
+	end(span, err)
+	return err
+}
+
+func (u *useCase) Delete(ctx context.Context, id person.ID) error {
+	ctx, span := u.tracer.Start(ctx, "person.UseCase/Delete", trace.WithAttributes(personID(id)))
+	err := u.next.Delete(ctx, id)
+	end(span, err)
+	return err
+}
+
+//repository é um person.Repository que cria um span para cada chamada a next
+type repository struct {
+	next   person.Repository
+	tracer trace.Tracer
+	system string
+}
+
+//Repository envolve o repositório de pessoas. Os spans se chamam person.Repository/<método> e informam
+//em db.system o banco de dados usado, como mysql
+func (t *Tracing) Repository(next person.Repository, system string) person.Repository {
+	return &repository{next: next, tracer: t.tracer, system: system}
+}
+
+func (r *repository) start(ctx context.Context, method string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append(opts,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemKey.String(r.system)),
+	)
+	return r.tracer.Start(ctx, "person.Repository/"+method, opts...)
+}
+
+func (r *repository) Get(ctx context.Context, id person.ID) (*person.Person, error) {
+	ctx, span := r.start(ctx, "Get", trace.WithAttributes(personID(id)))
+	p, err := r.next.Get(ctx, id)
+	end(span, err)
+	return p, err
+}
+
+func (r *repository) Search(ctx context.Context, query string, opts person.ListOptions) (*person.Page, error) {
+	ctx, span := r.start(ctx, "Search")
+	page, err := r.next.Search(ctx, query, opts)
+	end(span, err)
+	return page, err
+}
+
+func (r *repository) List(ctx context.Context, opts person.ListOptions) (*person.Page, error) {
+	ctx, span := r.start(ctx, "List")
+	page, err := r.next.List(ctx, opts)
+	end(span, err)
+	return page, err
+}
+
+func (r *repository) Create(ctx context.Context, e *person.Person) (person.ID, error) {
+	ctx, span := r.start(ctx, "Create")
+	id, err := r.next.Create(ctx, e)
+	if err == nil {
+		span.SetAttributes(personID(id))
+	}
+	end(span, err)
+	return id, err
+}
+
+func (r *repository) Update(ctx context.Context, e *person.Person) error {
+	ctx, span := r.start(ctx, "Update", trace.WithAttributes(personID(e.ID)))
+	err := r.next.Update(ctx, e)
+	end(span, err)
+	return err
+}
+
+func (r *repository) Delete(ctx context.Context, id person.ID) error {
+	ctx, span := r.start(ctx, "Delete", trace.WithAttributes(personID(id)))
+	err := r.next.Delete(ctx, id)
+	end(span, err)
+	return err
+}
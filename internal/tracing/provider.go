@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+//ServiceName é o nome do serviço informado nos spans exportados
+const ServiceName = "go-test-workshop"
+
+//NewProvider cria o TracerProvider com o exporter informado:
+//
+//	none    os spans são criados, e o trace propagado, mas não são exportados
+//	stdout  os spans são escritos em JSON em w
+//	otlp    os spans são enviados via OTLP/HTTP, configurado pelas variáveis OTEL_EXPORTER_OTLP_*
+//
+//A amostragem pode ser configurada com as variáveis OTEL_TRACES_SAMPLER e OTEL_TRACES_SAMPLER_ARG.
+//Chame Shutdown no provider antes de encerrar para exportar os spans pendentes
+func NewProvider(ctx context.Context, exporter string, w io.Writer) (*sdktrace.TracerProvider, error) {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(ServiceName))),
+	}
+	switch exporter {
+	case "none":
+	case "stdout":
+		e, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(e))
+	case "otlp":
+		e, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(e))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", exporter)
+	}
+	return sdktrace.NewTracerProvider(options...), nil
+}
@@ -0,0 +1,109 @@
+/*
+Package tracing cria os spans do OpenTelemetry da API.
+
+Assim como no pacote metrics, os spans são criados por um middleware do Echo e por decorators em
+volta de person.UseCase, person.Repository e weather.HTTPClient. O contexto de cada requisição é
+propagado pelos métodos dessas interfaces, então os spans do serviço, do repositório e das chamadas
+externas ficam dentro do span da requisição, e as chamadas HTTP enviam o cabeçalho traceparent (W3C).
+*/
+package tracing
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//instrumentation é o nome usado para identificar os spans criados por este pacote
+const instrumentation = "github.com/PicPay/go-test-workshop/internal/tracing"
+
+//Tracing cria os spans com o tracer de um TracerProvider. Nos testes use um provider com tracetest.NewInMemoryExporter
+type Tracing struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+//New cria os spans com tp e propaga o contexto no formato W3C (traceparent e baggage)
+func New(tp trace.TracerProvider) *Tracing {
+	return &Tracing{
+		tracer:     tp.Tracer(instrumentation),
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+}
+
+//Echo retorna o middleware que cria o span de cada requisição, continuando o trace recebido no cabeçalho traceparent.
+//O contexto com o span é colocado na requisição, então os handlers devem usar c.Request().Context().
+//Um erro do handler é registrado no span e continua sendo retornado, para que o log de acesso também o receba
+func (t *Tracing) Echo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := t.propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := t.tracer.Start(ctx, "HTTP "+req.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPMethodKey.String(req.Method),
+					semconv.HTTPTargetKey.String(req.URL.Path),
+				),
+			)
+			defer span.End()
+			c.SetRequest(req.WithContext(ctx))
+			err := next(c)
+			if err != nil {
+				span.RecordError(err)
+				c.Error(err)
+			}
+			status := c.Response().Status
+			if route := route(c, status); route != "" {
+				span.SetName(req.Method + " " + route)
+				span.SetAttributes(semconv.HTTPRouteKey.String(route))
+			}
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+			span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
+			return err
+		}
+	}
+}
+
+//route retorna a rota registrada, como /people/:id. Requisições sem rota recebem o próprio caminho em c.Path(),
+//então nesse caso retorna vazio para que o nome do span não tenha um valor diferente para cada URL
+func route(c echo.Context, status int) string {
+	if status != http.StatusNotFound {
+		return c.Path()
+	}
+	for _, r := range c.Echo().Routes() {
+		if r.Path == c.Path() {
+			return r.Path
+		}
+	}
+	return ""
+}
+
+//end registra o erro no span. Os erros do domínio, como person.ErrNotFound, são respostas esperadas
+//e não marcam o span como falha
+func end(span trace.Span, err error) {
+	defer span.End()
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	switch {
+	case errors.Is(err, person.ErrNotFound),
+		errors.Is(err, person.ErrInvalidPerson),
+		errors.Is(err, person.ErrConflict),
+		errors.Is(err, person.ErrInvalidListOptions):
+		return
+	}
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func personID(id person.ID) attribute.KeyValue {
+	return attribute.Int64("person.id", int64(id))
+}
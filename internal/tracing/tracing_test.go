@@ -0,0 +1,141 @@
+//go:build unit
+
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpecho "github.com/PicPay/go-test-workshop/internal/http/echo"
+	"github.com/PicPay/go-test-workshop/internal/tracing"
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/PicPay/go-test-workshop/person/inmem"
+	"github.com/PicPay/go-test-workshop/weather/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//newTracing cria um Tracing que guarda os spans finalizados em memória
+func newTracing() (*tracing.Tracing, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return tracing.New(tp), exporter
+}
+
+//spanNamed retorna o span finalizado com o nome informado
+func spanNamed(t *testing.T, exporter *tracetest.InMemoryExporter, name string) tracetest.SpanStub {
+	for _, s := range exporter.GetSpans() {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("span %s not found", name)
+	return tracetest.SpanStub{}
+}
+
+func attr(s tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestRequestTrace(t *testing.T) {
+	ctx := context.Background()
+	tr, exporter := newTracing()
+	repo := inmem.NewInMem()
+	_, err := repo.Create(ctx, &person.Person{Name: "Ronnie", LastName: "Dio"})
+	assert.Nil(t, err)
+	h := httpecho.Handlers(nil, tr.UseCase(person.NewService(tr.Repository(repo, "inmem"))), nil)
+	h.Use(tr.Echo())
+
+	req := httptest.NewRequest(http.MethodGet, "/hello/dio", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	server := spanNamed(t, exporter, "GET /hello/:lastname")
+	service := spanNamed(t, exporter, "person.UseCase/Search")
+	repository := spanNamed(t, exporter, "person.Repository/Search")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.True(t, server.Parent.IsRemote())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, int64(200), attr(server, "http.status_code").AsInt64())
+	assert.Equal(t, server.SpanContext.SpanID(), service.Parent.SpanID())
+	assert.Equal(t, service.SpanContext.SpanID(), repository.Parent.SpanID())
+	assert.Equal(t, server.SpanContext.TraceID(), repository.SpanContext.TraceID())
+	assert.Equal(t, "inmem", attr(repository, "db.system").AsString())
+
+	t.Run("pessoa não encontrada não é uma falha", func(t *testing.T) {
+		exporter.Reset()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/people/100", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		get := spanNamed(t, exporter, "person.UseCase/Get")
+		assert.Equal(t, codes.Unset, get.Status.Code)
+		assert.Len(t, get.Events, 1)
+		assert.Equal(t, int64(100), attr(get, "person.id").AsInt64())
+		server := spanNamed(t, exporter, "GET /people/:id")
+		assert.Equal(t, codes.Unset, server.Status.Code)
+	})
+	t.Run("rota inexistente", func(t *testing.T) {
+		exporter.Reset()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+		spanNamed(t, exporter, "HTTP GET")
+	})
+}
+
+func TestHTTPClient(t *testing.T) {
+	tr, exporter := newTracing()
+	var sent *http.Request
+	next := mocks.NewHTTPClient(t)
+	next.On("Do", mock.Anything).
+		Run(func(args mock.Arguments) { sent = args.Get(0).(*http.Request) }).
+		Return(&http.Response{StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(strings.NewReader(`{}`))}, nil).
+		Once()
+	client := tr.HTTPClient(next)
+	req := httptest.NewRequest(http.MethodGet, "https://api.openweathermap.org/data/2.5/weather?appid=secret&lat=-27.5969&lon=-48.5495", nil)
+	resp, err := client.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Empty(t, req.Header.Get("traceparent"))
+
+	span := spanNamed(t, exporter, "HTTP GET")
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+	assert.Equal(t, "00-"+span.SpanContext.TraceID().String()+"-"+span.SpanContext.SpanID().String()+"-01", sent.Header.Get("traceparent"))
+	assert.Equal(t, "https://api.openweathermap.org/data/2.5/weather", attr(span, "http.url").AsString())
+	assert.Equal(t, int64(503), attr(span, "http.status_code").AsInt64())
+	assert.Equal(t, codes.Error, span.Status.Code)
+}
+
+func TestNewProvider(t *testing.T) {
+	ctx := context.Background()
+	t.Run("stdout", func(t *testing.T) {
+		var out bytes.Buffer
+		tp, err := tracing.NewProvider(ctx, "stdout", &out)
+		assert.Nil(t, err)
+		_, s := tp.Tracer("test").Start(ctx, "test span")
+		s.End()
+		assert.Nil(t, tp.Shutdown(ctx))
+		assert.Contains(t, out.String(), `"Name":"test span"`)
+		assert.Contains(t, out.String(), `"go-test-workshop"`)
+	})
+	t.Run("exporter desconhecido", func(t *testing.T) {
+		_, err := tracing.NewProvider(ctx, "jaeger", nil)
+		assert.NotNil(t, err)
+	})
+}
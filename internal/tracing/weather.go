@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/PicPay/go-test-workshop/weather"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//httpClient é um weather.HTTPClient que cria um span para cada chamada e propaga o trace no cabeçalho traceparent
+type httpClient struct {
+	next       weather.HTTPClient
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+//HTTPClient envolve o cliente HTTP dos provedores. Deve ficar por dentro das novas tentativas, para que cada
+//tentativa tenha o seu span
+func (t *Tracing) HTTPClient(next weather.HTTPClient) weather.HTTPClient {
+	return &httpClient{next: next, tracer: t.tracer, propagator: t.propagator}
+}
+
+func (c *httpClient) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := c.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.HTTPURLKey.String(redact(req)),
+			semconv.NetPeerNameKey.String(req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+	req = req.Clone(ctx)
+	c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := c.next.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(resp.StatusCode, trace.SpanKindClient))
+	return resp, nil
+}
+
+//redact retorna a URL sem a query, que contém a chave da API
+func redact(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery = ""
+	u.User = nil
+	return u.String()
+}
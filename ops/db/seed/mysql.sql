@@ -0,0 +1,2 @@
+-- dados de exemplo para desenvolvimento no MySQL, executados depois das migrações quando seed_file está configurado
+insert ignore into person (id, first_name, last_name, created_at) values (1, 'Elton', 'Minetto', now());
@@ -0,0 +1,4 @@
+-- dados de exemplo para desenvolvimento no PostgreSQL, executados depois das migrações quando seed_file está configurado
+insert into person (id, first_name, last_name, created_at) values (1, 'Elton', 'Minetto', now()) on conflict (id) do nothing;
+-- o id foi informado explicitamente, então a sequência precisa ser ajustada para que o próximo insert não repita o 1
+select setval('person_id_seq', (select max(id) from person));
@@ -0,0 +1,18 @@
+package person
+
+import "errors"
+
+/*
+Erros do domínio. Repositórios e serviço devem retornar (ou embrulhar com %w) estes erros
+para que quem chama consiga diferenciar, com errors.Is, uma pessoa inexistente de uma falha no banco de dados
+*/
+var (
+	//ErrNotFound é retornado quando a pessoa procurada não existe
+	ErrNotFound = errors.New("not found")
+	//ErrInvalidPerson é retornado quando os dados da pessoa não são válidos
+	ErrInvalidPerson = errors.New("invalid person")
+	//ErrConflict é retornado quando a operação viola uma restrição de unicidade
+	ErrConflict = errors.New("conflict")
+	//ErrInvalidListOptions é retornado quando a paginação ou a ordenação solicitada não é suportada
+	ErrInvalidListOptions = errors.New("invalid list options")
+)
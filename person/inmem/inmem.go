@@ -0,0 +1,140 @@
+package inmem
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/PicPay/go-test-workshop/person"
+)
+
+//InMem repositório em memória, seguro para uso concorrente.
+//Útil para executar a API localmente e para testes que não precisam de um banco de dados real
+type InMem struct {
+	mu     sync.RWMutex
+	lastID person.ID
+	people map[person.ID]person.Person
+}
+
+//NewInMem create new repository
+func NewInMem() *InMem {
+	return &InMem{
+		people: make(map[person.ID]person.Person),
+	}
+}
+
+//Create a person
+func (r *InMem) Create(ctx context.Context, p *person.Person) (person.ID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastID++
+	saved := *p
+	saved.ID = r.lastID
+	r.people[saved.ID] = saved
+	return saved.ID, nil
+}
+
+//Get a person
+func (r *InMem) Get(ctx context.Context, id person.ID) (*person.Person, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.people[id]
+	if !ok {
+		return nil, person.ErrNotFound
+	}
+	return &p, nil
+}
+
+//Update a person
+func (r *InMem) Update(ctx context.Context, p *person.Person) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.people[p.ID]; !ok {
+		return person.ErrNotFound
+	}
+	r.people[p.ID] = *p
+	return nil
+}
+
+//Search person
+func (r *InMem) Search(ctx context.Context, query string, opts person.ListOptions) (*person.Page, error) {
+	query = strings.ToLower(query)
+	return r.page(opts, func(p person.Person) bool {
+		return strings.Contains(strings.ToLower(p.Name), query) ||
+			strings.Contains(strings.ToLower(p.LastName), query)
+	})
+}
+
+//List person
+func (r *InMem) List(ctx context.Context, opts person.ListOptions) (*person.Page, error) {
+	return r.page(opts, func(person.Person) bool {
+		return true
+	})
+}
+
+//Delete a person
+func (r *InMem) Delete(ctx context.Context, id person.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.people[id]; !ok {
+		return person.ErrNotFound
+	}
+	delete(r.people, id)
+	return nil
+}
+
+//page filtra, ordena e pagina as pessoas com a mesma semântica dos repositórios SQL
+func (r *InMem) page(opts person.ListOptions, match func(person.Person) bool) (*person.Page, error) {
+	r.mu.RLock()
+	people := []*person.Person{}
+	for _, p := range r.people {
+		if match(p) {
+			p := p
+			people = append(people, &p)
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(people, less(people, opts))
+
+	total := len(people)
+	start := opts.Offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if opts.Limit > 0 && start+opts.Limit < total {
+		end = start + opts.Limit
+	}
+	return &person.Page{
+		People: people[start:end],
+		Total:  total,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
+	}, nil
+}
+
+//less compara pelo campo escolhido, usando o id como critério de desempate
+func less(people []*person.Person, opts person.ListOptions) func(i, j int) bool {
+	key := func(p *person.Person) string {
+		switch opts.SortBy {
+		case person.SortByName:
+			return strings.ToLower(p.Name)
+		case person.SortByLastName:
+			return strings.ToLower(p.LastName)
+		default:
+			return ""
+		}
+	}
+	return func(i, j int) bool {
+		a, b := people[i], people[j]
+		if opts.Desc {
+			a, b = b, a
+		}
+		ka, kb := key(a), key(b)
+		if ka != kb {
+			return ka < kb
+		}
+		return a.ID < b.ID
+	}
+}
@@ -0,0 +1,38 @@
+//go:build unit
+
+package inmem_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/PicPay/go-test-workshop/person/inmem"
+	"github.com/PicPay/go-test-workshop/person/repotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) person.Repository {
+		return inmem.NewInMem()
+	})
+}
+
+func TestConcurrentCreate(t *testing.T) {
+	ctx := context.Background()
+	repo := inmem.NewInMem()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Create(ctx, &person.Person{Name: "Ozzy", LastName: "Osbourne"})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	result, err := repo.List(ctx, person.ListOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 50, result.Total)
+}
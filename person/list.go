@@ -0,0 +1,63 @@
+package person
+
+import "fmt"
+
+const (
+	//DefaultLimit é o tamanho de página usado quando nenhum limite é informado
+	DefaultLimit = 20
+	//MaxLimit é o maior tamanho de página aceito pelo serviço
+	MaxLimit = 100
+)
+
+//SortField define por qual campo a listagem é ordenada
+type SortField string
+
+const (
+	SortByID       SortField = "id"
+	SortByName     SortField = "name"
+	SortByLastName SortField = "last_name"
+)
+
+//ListOptions define a paginação e a ordenação de List e Search.
+//Para os repositórios, Limit <= 0 significa "sem limite" e SortBy vazio significa ordenar por ID
+type ListOptions struct {
+	Limit  int
+	Offset int
+	SortBy SortField
+	Desc   bool
+}
+
+//Page é uma página do resultado, junto com o total de registros que atendem à consulta
+type Page struct {
+	People []*Person `json:"people"`
+	Total  int       `json:"total"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+}
+
+//Validate verifica se as opções de listagem são suportadas
+func (o ListOptions) Validate() error {
+	switch o.SortBy {
+	case "", SortByID, SortByName, SortByLastName:
+	default:
+		return fmt.Errorf("%w: unknown sort field %q", ErrInvalidListOptions, o.SortBy)
+	}
+	if o.Limit < 0 || o.Offset < 0 {
+		return fmt.Errorf("%w: limit and offset must not be negative", ErrInvalidListOptions)
+	}
+	if o.Limit > MaxLimit {
+		return fmt.Errorf("%w: limit must not be greater than %d", ErrInvalidListOptions, MaxLimit)
+	}
+	return nil
+}
+
+//WithDefaults preenche os campos não informados com os valores padrão
+func (o ListOptions) WithDefaults() ListOptions {
+	if o.Limit == 0 {
+		o.Limit = DefaultLimit
+	}
+	if o.SortBy == "" {
+		o.SortBy = SortByID
+	}
+	return o
+}
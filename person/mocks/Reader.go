@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	person "github.com/PicPay/go-test-workshop/person"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type Reader struct {
 	mock.Mock
 }
 
-// Get provides a mock function with given fields: id
-func (_m *Reader) Get(id person.ID) (*person.Person, error) {
-	ret := _m.Called(id)
+// Get provides a mock function with given fields: ctx, id
+func (_m *Reader) Get(ctx context.Context, id person.ID) (*person.Person, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *person.Person
-	if rf, ok := ret.Get(0).(func(person.ID) *person.Person); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, person.ID) *person.Person); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*person.Person)
@@ -26,8 +28,8 @@ func (_m *Reader) Get(id person.ID) (*person.Person, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(person.ID) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, person.ID) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -35,22 +37,22 @@ func (_m *Reader) Get(id person.ID) (*person.Person, error) {
 	return r0, r1
 }
 
-// List provides a mock function with given fields:
-func (_m *Reader) List() ([]*person.Person, error) {
-	ret := _m.Called()
+// List provides a mock function with given fields: ctx, opts
+func (_m *Reader) List(ctx context.Context, opts person.ListOptions) (*person.Page, error) {
+	ret := _m.Called(ctx, opts)
 
-	var r0 []*person.Person
-	if rf, ok := ret.Get(0).(func() []*person.Person); ok {
-		r0 = rf()
+	var r0 *person.Page
+	if rf, ok := ret.Get(0).(func(context.Context, person.ListOptions) *person.Page); ok {
+		r0 = rf(ctx, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*person.Person)
+			r0 = ret.Get(0).(*person.Page)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, person.ListOptions) error); ok {
+		r1 = rf(ctx, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -58,22 +60,22 @@ func (_m *Reader) List() ([]*person.Person, error) {
 	return r0, r1
 }
 
-// Search provides a mock function with given fields: query
-func (_m *Reader) Search(query string) ([]*person.Person, error) {
-	ret := _m.Called(query)
+// Search provides a mock function with given fields: ctx, query, opts
+func (_m *Reader) Search(ctx context.Context, query string, opts person.ListOptions) (*person.Page, error) {
+	ret := _m.Called(ctx, query, opts)
 
-	var r0 []*person.Person
-	if rf, ok := ret.Get(0).(func(string) []*person.Person); ok {
-		r0 = rf(query)
+	var r0 *person.Page
+	if rf, ok := ret.Get(0).(func(context.Context, string, person.ListOptions) *person.Page); ok {
+		r0 = rf(ctx, query, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*person.Person)
+			r0 = ret.Get(0).(*person.Page)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(query)
+	if rf, ok := ret.Get(1).(func(context.Context, string, person.ListOptions) error); ok {
+		r1 = rf(ctx, query, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
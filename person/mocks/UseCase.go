@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	person "github.com/PicPay/go-test-workshop/person"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,20 +14,20 @@ type UseCase struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: firstName, lastName
-func (_m *UseCase) Create(firstName string, lastName string) (person.ID, error) {
-	ret := _m.Called(firstName, lastName)
+// Create provides a mock function with given fields: ctx, firstName, lastName
+func (_m *UseCase) Create(ctx context.Context, firstName string, lastName string) (person.ID, error) {
+	ret := _m.Called(ctx, firstName, lastName)
 
 	var r0 person.ID
-	if rf, ok := ret.Get(0).(func(string, string) person.ID); ok {
-		r0 = rf(firstName, lastName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) person.ID); ok {
+		r0 = rf(ctx, firstName, lastName)
 	} else {
 		r0 = ret.Get(0).(person.ID)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(firstName, lastName)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, firstName, lastName)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -33,13 +35,13 @@ func (_m *UseCase) Create(firstName string, lastName string) (person.ID, error)
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *UseCase) Delete(id person.ID) error {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *UseCase) Delete(ctx context.Context, id person.ID) error {
+	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(person.ID) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, person.ID) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -47,13 +49,13 @@ func (_m *UseCase) Delete(id person.ID) error {
 	return r0
 }
 
-// Get provides a mock function with given fields: id
-func (_m *UseCase) Get(id person.ID) (*person.Person, error) {
-	ret := _m.Called(id)
+// Get provides a mock function with given fields: ctx, id
+func (_m *UseCase) Get(ctx context.Context, id person.ID) (*person.Person, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *person.Person
-	if rf, ok := ret.Get(0).(func(person.ID) *person.Person); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, person.ID) *person.Person); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*person.Person)
@@ -61,8 +63,8 @@ func (_m *UseCase) Get(id person.ID) (*person.Person, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(person.ID) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, person.ID) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,22 +72,22 @@ func (_m *UseCase) Get(id person.ID) (*person.Person, error) {
 	return r0, r1
 }
 
-// List provides a mock function with given fields:
-func (_m *UseCase) List() ([]*person.Person, error) {
-	ret := _m.Called()
+// List provides a mock function with given fields: ctx, opts
+func (_m *UseCase) List(ctx context.Context, opts person.ListOptions) (*person.Page, error) {
+	ret := _m.Called(ctx, opts)
 
-	var r0 []*person.Person
-	if rf, ok := ret.Get(0).(func() []*person.Person); ok {
-		r0 = rf()
+	var r0 *person.Page
+	if rf, ok := ret.Get(0).(func(context.Context, person.ListOptions) *person.Page); ok {
+		r0 = rf(ctx, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*person.Person)
+			r0 = ret.Get(0).(*person.Page)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, person.ListOptions) error); ok {
+		r1 = rf(ctx, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -93,22 +95,22 @@ func (_m *UseCase) List() ([]*person.Person, error) {
 	return r0, r1
 }
 
-// Search provides a mock function with given fields: query
-func (_m *UseCase) Search(query string) ([]*person.Person, error) {
-	ret := _m.Called(query)
+// Search provides a mock function with given fields: ctx, query, opts
+func (_m *UseCase) Search(ctx context.Context, query string, opts person.ListOptions) (*person.Page, error) {
+	ret := _m.Called(ctx, query, opts)
 
-	var r0 []*person.Person
-	if rf, ok := ret.Get(0).(func(string) []*person.Person); ok {
-		r0 = rf(query)
+	var r0 *person.Page
+	if rf, ok := ret.Get(0).(func(context.Context, string, person.ListOptions) *person.Page); ok {
+		r0 = rf(ctx, query, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*person.Person)
+			r0 = ret.Get(0).(*person.Page)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(query)
+	if rf, ok := ret.Get(1).(func(context.Context, string, person.ListOptions) error); ok {
+		r1 = rf(ctx, query, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -116,13 +118,13 @@ func (_m *UseCase) Search(query string) ([]*person.Person, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: e
-func (_m *UseCase) Update(e *person.Person) error {
-	ret := _m.Called(e)
+// Update provides a mock function with given fields: ctx, e
+func (_m *UseCase) Update(ctx context.Context, e *person.Person) error {
+	ret := _m.Called(ctx, e)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*person.Person) error); ok {
-		r0 = rf(e)
+	if rf, ok := ret.Get(0).(func(context.Context, *person.Person) error); ok {
+		r0 = rf(ctx, e)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	person "github.com/PicPay/go-test-workshop/person"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,20 +14,20 @@ type Writer struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: e
-func (_m *Writer) Create(e *person.Person) (person.ID, error) {
-	ret := _m.Called(e)
+// Create provides a mock function with given fields: ctx, e
+func (_m *Writer) Create(ctx context.Context, e *person.Person) (person.ID, error) {
+	ret := _m.Called(ctx, e)
 
 	var r0 person.ID
-	if rf, ok := ret.Get(0).(func(*person.Person) person.ID); ok {
-		r0 = rf(e)
+	if rf, ok := ret.Get(0).(func(context.Context, *person.Person) person.ID); ok {
+		r0 = rf(ctx, e)
 	} else {
 		r0 = ret.Get(0).(person.ID)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*person.Person) error); ok {
-		r1 = rf(e)
+	if rf, ok := ret.Get(1).(func(context.Context, *person.Person) error); ok {
+		r1 = rf(ctx, e)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -33,13 +35,13 @@ func (_m *Writer) Create(e *person.Person) (person.ID, error) {
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *Writer) Delete(id person.ID) error {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Writer) Delete(ctx context.Context, id person.ID) error {
+	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(person.ID) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, person.ID) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -47,13 +49,13 @@ func (_m *Writer) Delete(id person.ID) error {
 	return r0
 }
 
-// Update provides a mock function with given fields: e
-func (_m *Writer) Update(e *person.Person) error {
-	ret := _m.Called(e)
+// Update provides a mock function with given fields: ctx, e
+func (_m *Writer) Update(ctx context.Context, e *person.Person) error {
+	ret := _m.Called(ctx, e)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*person.Person) error); ok {
-		r0 = rf(e)
+	if rf, ok := ret.Get(0).(func(context.Context, *person.Person) error); ok {
+		r0 = rf(ctx, e)
 	} else {
 		r0 = ret.Error(0)
 	}
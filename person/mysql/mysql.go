@@ -1,14 +1,20 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/PicPay/go-test-workshop/person"
+	driver "github.com/go-sql-driver/mysql"
 )
 
+//erDupEntry é o código retornado pelo MySQL quando uma chave única é violada
+const erDupEntry = 1062
+
 //MySQL mysql repo
 type MySQL struct {
 	db *sql.DB
@@ -22,21 +28,21 @@ func NewMySQL(db *sql.DB) *MySQL {
 }
 
 //Create a person
-func (r *MySQL) Create(p *person.Person) (person.ID, error) {
-	stmt, err := r.db.Prepare(`
+func (r *MySQL) Create(ctx context.Context, p *person.Person) (person.ID, error) {
+	stmt, err := r.db.PrepareContext(ctx, `
 		insert into person (first_name, last_name, created_at) 
 		values(?,?,?)`)
-	defer stmt.Close()
 	if err != nil {
 		return 0, err
 	}
-	res, err := stmt.Exec(
+	defer stmt.Close()
+	res, err := stmt.ExecContext(ctx,
 		p.Name,
 		p.LastName,
 		time.Now().Format("2006-01-02"),
 	)
 	if err != nil {
-		return 0, err
+		return 0, mapError(err)
 	}
 	id, err := res.LastInsertId()
 	if err != nil {
@@ -46,18 +52,23 @@ func (r *MySQL) Create(p *person.Person) (person.ID, error) {
 }
 
 //Get a person
-func (r *MySQL) Get(id person.ID) (*person.Person, error) {
-	stmt, err := r.db.Prepare(`select id, first_name, last_name from person where id = ?`)
+func (r *MySQL) Get(ctx context.Context, id person.ID) (*person.Person, error) {
+	stmt, err := r.db.PrepareContext(ctx, `select id, first_name, last_name from person where id = ?`)
 	if err != nil {
 		return nil, err
 	}
+	defer stmt.Close()
 	var p person.Person
-	rows, err := stmt.Query(id)
+	rows, err := stmt.QueryContext(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	if !rows.Next() {
-		return nil, fmt.Errorf("not found")
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, person.ErrNotFound
 	}
 	err = rows.Scan(&p.ID, &p.Name, &p.LastName)
 	if err != nil {
@@ -67,54 +78,52 @@ func (r *MySQL) Get(id person.ID) (*person.Person, error) {
 }
 
 //Update a person
-func (r *MySQL) Update(p *person.Person) error {
-	_, err := r.db.Exec("update person set first_name = ?, last_name = ?, updated_at = ? where id = ?", p.Name, p.LastName, time.Now().Format("2006-01-02"), p.ID)
+func (r *MySQL) Update(ctx context.Context, p *person.Person) error {
+	_, err := r.Get(ctx, p.ID)
 	if err != nil {
 		return err
 	}
+	_, err = r.db.ExecContext(ctx, "update person set first_name = ?, last_name = ?, updated_at = ? where id = ?", p.Name, p.LastName, time.Now().Format("2006-01-02"), p.ID)
+	if err != nil {
+		return mapError(err)
+	}
 	return nil
 }
 
 //Search person
-func (r *MySQL) Search(query string) ([]*person.Person, error) {
-	stmt, err := r.db.Prepare(`select id, first_name, last_name from person where first_name like ? or last_name like ?`)
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-	var people []*person.Person
+func (r *MySQL) Search(ctx context.Context, query string, opts person.ListOptions) (*person.Page, error) {
 	query = "%" + strings.ToLower(query) + "%"
-	rows, err := stmt.Query(query, query)
+	return r.page(ctx, "where first_name like ? or last_name like ?", []interface{}{query, query}, opts)
+}
+
+//List person
+func (r *MySQL) List(ctx context.Context, opts person.ListOptions) (*person.Page, error) {
+	return r.page(ctx, "", nil, opts)
+}
+
+//page executa a contagem e a consulta paginada com o filtro where
+func (r *MySQL) page(ctx context.Context, where string, args []interface{}, opts person.ListOptions) (*person.Page, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "select count(*) from person "+where, args...).Scan(&total)
 	if err != nil {
 		return nil, err
 	}
-	for rows.Next() {
-		var p person.Person
-		err = rows.Scan(&p.ID, &p.Name, &p.LastName)
-		if err != nil {
-			return nil, err
-		}
-		people = append(people, &p)
+	query := fmt.Sprintf("select id, first_name, last_name from person %s order by %s", where, orderBy(opts))
+	if opts.Limit > 0 {
+		query += " limit ? offset ?"
+		args = append(args, opts.Limit, opts.Offset)
 	}
-	if len(people) == 0 {
-		return nil, fmt.Errorf("not found")
-	}
-
-	return people, nil
-}
-
-//List person
-func (r *MySQL) List() ([]*person.Person, error) {
-	stmt, err := r.db.Prepare(`select id, first_name, last_name from person`)
+	stmt, err := r.db.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
-	var people []*person.Person
-	rows, err := stmt.Query()
+	people := []*person.Person{}
+	rows, err := stmt.QueryContext(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	for rows.Next() {
 		var p person.Person
 		err = rows.Scan(&p.ID, &p.Name, &p.LastName)
@@ -123,22 +132,52 @@ func (r *MySQL) List() ([]*person.Person, error) {
 		}
 		people = append(people, &p)
 	}
-	if len(people) == 0 {
-		return nil, fmt.Errorf("not found")
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return people, nil
+	return &person.Page{
+		People: people,
+		Total:  total,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
+	}, nil
 }
 
 //Delete a person
-func (r *MySQL) Delete(id person.ID) error {
-	p, _ := r.Get(id)
-	if p == nil {
-		return fmt.Errorf("not found")
+func (r *MySQL) Delete(ctx context.Context, id person.ID) error {
+	_, err := r.Get(ctx, id)
+	if err != nil {
+		return err
 	}
-	_, err := r.db.Exec("delete from person where id = ?", id)
+	_, err = r.db.ExecContext(ctx, "delete from person where id = ?", id)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+//mapError traduz os erros do driver para os erros do domínio
+func mapError(err error) error {
+	var me *driver.MySQLError
+	if errors.As(err, &me) && me.Number == erDupEntry {
+		return fmt.Errorf("%w: %s", person.ErrConflict, me.Message)
+	}
+	return err
+}
+
+//orderBy monta a cláusula de ordenação. O id é usado como critério de desempate para que a paginação seja estável
+func orderBy(opts person.ListOptions) string {
+	dir := "asc"
+	if opts.Desc {
+		dir = "desc"
+	}
+	switch opts.SortBy {
+	case person.SortByName:
+		return fmt.Sprintf("first_name %s, id %s", dir, dir)
+	case person.SortByLastName:
+		return fmt.Sprintf("last_name %s, id %s", dir, dir)
+	default:
+		return "id " + dir
+	}
+}
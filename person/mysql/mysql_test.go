@@ -5,9 +5,10 @@ package mysql_test
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"github.com/PicPay/go-test-workshop/person"
 	"github.com/PicPay/go-test-workshop/person/mysql"
+	"github.com/PicPay/go-test-workshop/person/persontest"
+	"github.com/PicPay/go-test-workshop/person/repotest"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -15,7 +16,7 @@ import (
 
 func TestCRUD(t *testing.T) {
 	ctx := context.Background()
-	container, err := person.SetupMysqL(ctx)
+	container, err := persontest.SetupMysqL(ctx)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -25,11 +26,11 @@ func TestCRUD(t *testing.T) {
 		t.Error(err)
 	}
 	defer db.Close()
-	err = person.InitMySQL(ctx, db)
+	err = persontest.InitMySQL(ctx, db)
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer person.TruncateMySQL(ctx, db)
+	defer persontest.TruncateMySQL(ctx, db)
 
 	repo := mysql.NewMySQL(db)
 
@@ -38,49 +39,55 @@ func TestCRUD(t *testing.T) {
 			Name:     "Ozzy",
 			LastName: "Osbourne",
 		}
-		id, err := repo.Create(p)
+		id, err := repo.Create(ctx, p)
 		assert.Equal(t, person.ID(1), id)
 		assert.Nil(t, err)
 	})
 	t.Run("recuperar person", func(t *testing.T) {
-		result, err := repo.Get(person.ID(1))
+		result, err := repo.Get(ctx, person.ID(1))
 		assert.Equal(t, "Ozzy", result.Name)
 		assert.Nil(t, err)
 	})
 	t.Run("atualizar person", func(t *testing.T) {
-		result, err := repo.Get(person.ID(1))
+		result, err := repo.Get(ctx, person.ID(1))
 		assert.Nil(t, err)
 		result.Name = "Novo nome"
-		err = repo.Update(result)
+		err = repo.Update(ctx, result)
 		assert.Nil(t, err)
-		saved, err := repo.Get(person.ID(1))
+		saved, err := repo.Get(ctx, person.ID(1))
 		assert.Nil(t, err)
 		assert.Equal(t, "Novo nome", saved.Name)
 	})
 	t.Run("listar person", func(t *testing.T) {
-		result, err := repo.List()
-		assert.Equal(t, 1, len(result))
-		assert.Equal(t, "Osbourne", result[0].LastName)
+		result, err := repo.List(ctx, person.ListOptions{})
+		assert.Equal(t, 1, result.Total)
+		assert.Equal(t, 1, len(result.People))
+		assert.Equal(t, "Osbourne", result.People[0].LastName)
 		assert.Nil(t, err)
 	})
 	t.Run("remover person", func(t *testing.T) {
-		err := repo.Delete(person.ID(1))
+		err := repo.Delete(ctx, person.ID(1))
 		assert.Nil(t, err)
 	})
 	t.Run("listar person vazia", func(t *testing.T) {
-		result, err := repo.List()
-		assert.Nil(t, result)
-		assert.Errorf(t, err, "not found")
+		result, err := repo.List(ctx, person.ListOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, result.Total)
+		assert.Empty(t, result.People)
 	})
 	t.Run("remover person não existente", func(t *testing.T) {
-		err := repo.Delete(person.ID(1))
-		assert.Errorf(t, err, "not found")
+		err := repo.Delete(ctx, person.ID(1))
+		assert.ErrorIs(t, err, person.ErrNotFound)
+	})
+	t.Run("atualizar person não existente", func(t *testing.T) {
+		err := repo.Update(ctx, &person.Person{ID: person.ID(1), Name: "Ozzy", LastName: "Osbourne"})
+		assert.ErrorIs(t, err, person.ErrNotFound)
 	})
 }
 
 func TestSearch(t *testing.T) {
 	ctx := context.Background()
-	container, err := person.SetupMysqL(ctx)
+	container, err := persontest.SetupMysqL(ctx)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -90,11 +97,11 @@ func TestSearch(t *testing.T) {
 		t.Error(err)
 	}
 	defer db.Close()
-	err = person.InitMySQL(ctx, db)
+	err = persontest.InitMySQL(ctx, db)
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer person.TruncateMySQL(ctx, db)
+	defer persontest.TruncateMySQL(ctx, db)
 
 	repo := mysql.NewMySQL(db)
 
@@ -106,9 +113,9 @@ func TestSearch(t *testing.T) {
 		Name:     "Ronnie",
 		LastName: "Dio",
 	}
-	p1.ID, err = repo.Create(p1)
+	p1.ID, err = repo.Create(ctx, p1)
 	assert.Nil(t, err)
-	p2.ID, err = repo.Create(p2)
+	p2.ID, err = repo.Create(ctx, p2)
 	assert.Nil(t, err)
 
 	tests := []struct {
@@ -158,19 +165,49 @@ func TestSearch(t *testing.T) {
 		},
 		{
 			query:       "Tony",
-			result:      nil,
-			expectedErr: fmt.Errorf("not found"),
+			result:      []*person.Person{},
+			expectedErr: nil,
 		},
 		{
 			query:       "martin",
-			result:      nil,
-			expectedErr: fmt.Errorf("not found"),
+			result:      []*person.Person{},
+			expectedErr: nil,
 		},
 	}
 	for _, test := range tests {
-		found, err := repo.Search(test.query)
+		found, err := repo.Search(ctx, test.query, person.ListOptions{})
 		assert.Equal(t, test.expectedErr, err)
-		assert.Equal(t, test.result, found)
+		if test.result == nil {
+			assert.Nil(t, found)
+			continue
+		}
+		assert.Equal(t, test.result, found.People)
 	}
 
 }
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	container, err := persontest.SetupMysqL(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer container.Terminate(ctx)
+	db, err := sql.Open("mysql", container.URI)
+	if err != nil {
+		t.Error(err)
+	}
+	defer db.Close()
+	err = persontest.InitMySQL(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repotest.Run(t, func(t *testing.T) person.Repository {
+		err := persontest.TruncateMySQL(ctx, db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mysql.NewMySQL(db)
+	})
+}
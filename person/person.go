@@ -1,5 +1,10 @@
 package person
 
+import (
+	"context"
+	"fmt"
+)
+
 //ID representa o ID de uma entidade.
 //É uma boa prática criarmos esse tipo, pois se em algum momento precisarmos mudar para outro formato (UUID por exemplo)
 //não quebramos o restante do projeto
@@ -7,21 +12,32 @@ type ID int
 
 //Person define o que é uma pessoa
 type Person struct {
-	ID       ID
-	Name     string
-	LastName string
+	ID       ID     `json:"id"`
+	Name     string `json:"name"`
+	LastName string `json:"last_name"`
+}
+
+//Validate verifica se a pessoa possui os dados obrigatórios
+func (p *Person) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPerson)
+	}
+	if p.LastName == "" {
+		return fmt.Errorf("%w: last name is required", ErrInvalidPerson)
+	}
+	return nil
 }
 
 type Reader interface {
-	Get(id ID) (*Person, error)
-	Search(query string) ([]*Person, error)
-	List() ([]*Person, error)
+	Get(ctx context.Context, id ID) (*Person, error)
+	Search(ctx context.Context, query string, opts ListOptions) (*Page, error)
+	List(ctx context.Context, opts ListOptions) (*Page, error)
 }
 
 type Writer interface {
-	Create(e *Person) (ID, error)
-	Update(e *Person) error
-	Delete(id ID) error
+	Create(ctx context.Context, e *Person) (ID, error)
+	Update(ctx context.Context, e *Person) error
+	Delete(ctx context.Context, id ID) error
 }
 
 type Repository interface {
@@ -41,10 +57,10 @@ implementação vai ser feita no pacote person/mysql
 */
 
 type UseCase interface {
-	Get(id ID) (*Person, error)
-	Search(query string) ([]*Person, error)
-	List() ([]*Person, error)
-	Create(firstName, lastName string) (ID, error)
-	Update(e *Person) error
-	Delete(id ID) error
+	Get(ctx context.Context, id ID) (*Person, error)
+	Search(ctx context.Context, query string, opts ListOptions) (*Page, error)
+	List(ctx context.Context, opts ListOptions) (*Page, error)
+	Create(ctx context.Context, firstName, lastName string) (ID, error)
+	Update(ctx context.Context, e *Person) error
+	Delete(ctx context.Context, id ID) error
 }
@@ -0,0 +1,139 @@
+/*
+Package persontest sobe os bancos de dados usados nos testes de integração e e2e dos repositórios de person.
+
+Fica fora do pacote person para que quem usa o domínio não compile o testcontainers e as migrações junto:
+
+	container, err := persontest.SetupPostgres(ctx)
+	db, err := sql.Open("postgres", container.URI)
+	err = persontest.InitPostgres(ctx, db)
+*/
+package persontest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/PicPay/go-test-workshop/internal/migrations"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+type MysqlDBContainer struct {
+	testcontainers.Container
+	URI string
+}
+
+const (
+	dbUser         = "workshop"
+	dbPassword     = "workshop"
+	database       = "workshop"
+	dbRootPassword = "db-root-password"
+)
+
+func SetupMysqL(ctx context.Context) (*MysqlDBContainer, error) {
+	req := testcontainers.ContainerRequest{
+		Image:        "mariadb:latest",
+		ExposedPorts: []string{"3306/tcp"},
+		WaitingFor:   wait.ForLog("Version: '10.8.3-MariaDB-1:10.8.3+maria~jammy'  socket: '/run/mysqld/mysqld.sock'  port: 3306  mariadb.org binary distribution"),
+		Env: map[string]string{
+			"MARIADB_USER":          dbUser,
+			"MARIADB_PASSWORD":      dbPassword,
+			"MARIADB_ROOT_PASSWORD": dbRootPassword,
+			"MARIADB_DATABASE":      database,
+		},
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mappedPort, err := container.MappedPort(ctx, "3306")
+	if err != nil {
+		return nil, err
+	}
+
+	hostIP, err := container.Host(ctx)
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", "root", dbRootPassword, hostIP, mappedPort.Port(), database)
+
+	return &MysqlDBContainer{Container: container, URI: uri}, nil
+}
+
+//InitMySQL cria o schema usando as mesmas migrações executadas em produção
+func InitMySQL(ctx context.Context, db *sql.DB) error {
+	m, err := migrations.New(db, migrations.MySQL)
+	if err != nil {
+		return err
+	}
+	return m.Up(ctx)
+}
+
+func TruncateMySQL(ctx context.Context, db *sql.DB) error {
+	query := []string{
+		fmt.Sprintf("use %s;", database),
+		"truncate table person",
+	}
+	for _, q := range query {
+		_, err := db.ExecContext(ctx, q)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type PostgresDBContainer struct {
+	testcontainers.Container
+	URI string
+}
+
+func SetupPostgres(ctx context.Context) (*PostgresDBContainer, error) {
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:14-alpine",
+		ExposedPorts: []string{"5432/tcp"},
+		//o postgres reinicia uma vez durante a inicialização, por isso esperamos a segunda ocorrência
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+		Env: map[string]string{
+			"POSTGRES_USER":     dbUser,
+			"POSTGRES_PASSWORD": dbPassword,
+			"POSTGRES_DB":       database,
+		},
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mappedPort, err := container.MappedPort(ctx, "5432")
+	if err != nil {
+		return nil, err
+	}
+
+	hostIP, err := container.Host(ctx)
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", dbUser, dbPassword, hostIP, mappedPort.Port(), database)
+
+	return &PostgresDBContainer{Container: container, URI: uri}, nil
+}
+
+//InitPostgres cria o schema usando as mesmas migrações executadas em produção
+func InitPostgres(ctx context.Context, db *sql.DB) error {
+	m, err := migrations.New(db, migrations.Postgres)
+	if err != nil {
+		return err
+	}
+	return m.Up(ctx)
+}
+
+func TruncatePostgres(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "truncate table person restart identity")
+	return err
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/lib/pq"
+)
+
+//uniqueViolation é o código retornado pelo Postgres quando uma chave única é violada
+const uniqueViolation = "23505"
+
+//Postgres postgres repo
+type Postgres struct {
+	db *sql.DB
+}
+
+//NewPostgres create new repository
+func NewPostgres(db *sql.DB) *Postgres {
+	return &Postgres{
+		db: db,
+	}
+}
+
+//Create a person
+func (r *Postgres) Create(ctx context.Context, p *person.Person) (person.ID, error) {
+	var id person.ID
+	err := r.db.QueryRowContext(ctx, `
+		insert into person (first_name, last_name, created_at)
+		values($1,$2,$3)
+		returning id`,
+		p.Name,
+		p.LastName,
+		time.Now(),
+	).Scan(&id)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return id, nil
+}
+
+//Get a person
+func (r *Postgres) Get(ctx context.Context, id person.ID) (*person.Person, error) {
+	var p person.Person
+	err := r.db.QueryRowContext(ctx, `select id, first_name, last_name from person where id = $1`, id).
+		Scan(&p.ID, &p.Name, &p.LastName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, person.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+//Update a person
+func (r *Postgres) Update(ctx context.Context, p *person.Person) error {
+	res, err := r.db.ExecContext(ctx, "update person set first_name = $1, last_name = $2, updated_at = $3 where id = $4", p.Name, p.LastName, time.Now(), p.ID)
+	if err != nil {
+		return mapError(err)
+	}
+	return notFoundIfNoRows(res)
+}
+
+//Search person
+func (r *Postgres) Search(ctx context.Context, query string, opts person.ListOptions) (*person.Page, error) {
+	query = "%" + query + "%"
+	return r.page(ctx, "where first_name ilike $1 or last_name ilike $1", []interface{}{query}, opts)
+}
+
+//List person
+func (r *Postgres) List(ctx context.Context, opts person.ListOptions) (*person.Page, error) {
+	return r.page(ctx, "", nil, opts)
+}
+
+//Delete a person
+func (r *Postgres) Delete(ctx context.Context, id person.ID) error {
+	res, err := r.db.ExecContext(ctx, "delete from person where id = $1", id)
+	if err != nil {
+		return err
+	}
+	return notFoundIfNoRows(res)
+}
+
+//page executa a contagem e a consulta paginada com o filtro where
+func (r *Postgres) page(ctx context.Context, where string, args []interface{}, opts person.ListOptions) (*person.Page, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "select count(*) from person "+where, args...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("select id, first_name, last_name from person %s order by %s", where, orderBy(opts))
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" limit $%d offset $%d", len(args)+1, len(args)+2)
+		args = append(args, opts.Limit, opts.Offset)
+	}
+	people := []*person.Person{}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p person.Person
+		err = rows.Scan(&p.ID, &p.Name, &p.LastName)
+		if err != nil {
+			return nil, err
+		}
+		people = append(people, &p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &person.Page{
+		People: people,
+		Total:  total,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
+	}, nil
+}
+
+//orderBy monta a cláusula de ordenação. O id é usado como critério de desempate para que a paginação seja estável.
+//Os nomes são comparados em minúsculas para manter o mesmo comportamento da collation do MySQL
+func orderBy(opts person.ListOptions) string {
+	dir := "asc"
+	if opts.Desc {
+		dir = "desc"
+	}
+	switch opts.SortBy {
+	case person.SortByName:
+		return fmt.Sprintf("lower(first_name) %s, id %s", dir, dir)
+	case person.SortByLastName:
+		return fmt.Sprintf("lower(last_name) %s, id %s", dir, dir)
+	default:
+		return "id " + dir
+	}
+}
+
+//notFoundIfNoRows retorna person.ErrNotFound quando nenhuma linha foi afetada
+func notFoundIfNoRows(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return person.ErrNotFound
+	}
+	return nil
+}
+
+//mapError traduz os erros do driver para os erros do domínio
+func mapError(err error) error {
+	var pe *pq.Error
+	if errors.As(err, &pe) && pe.Code == uniqueViolation {
+		return fmt.Errorf("%w: %s", person.ErrConflict, pe.Message)
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/PicPay/go-test-workshop/person/persontest"
+	"github.com/PicPay/go-test-workshop/person/postgres"
+	"github.com/PicPay/go-test-workshop/person/repotest"
+	_ "github.com/lib/pq"
+)
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	container, err := persontest.SetupPostgres(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer container.Terminate(ctx)
+	db, err := sql.Open("postgres", container.URI)
+	if err != nil {
+		t.Error(err)
+	}
+	defer db.Close()
+	err = persontest.InitPostgres(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repotest.Run(t, func(t *testing.T) person.Repository {
+		err := persontest.TruncatePostgres(ctx, db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return postgres.NewPostgres(db)
+	})
+}
@@ -0,0 +1,250 @@
+/*
+Package repotest contém a suíte de testes de conformidade de person.Repository.
+
+Todo backend (mysql, inmem, ...) deve executar esta suíte nos seus próprios testes, garantindo
+que as implementações se comportem exatamente da mesma forma:
+
+	func TestRepository(t *testing.T) {
+		repotest.Run(t, func(t *testing.T) person.Repository {
+			return inmem.NewInMem()
+		})
+	}
+*/
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//Factory deve retornar um repositório vazio a cada chamada
+type Factory func(t *testing.T) person.Repository
+
+//Run executa todos os contratos de person.Reader e person.Writer no repositório criado por newRepo
+func Run(t *testing.T, newRepo Factory) {
+	t.Run("Create", func(t *testing.T) { testCreate(t, newRepo(t)) })
+	t.Run("Get", func(t *testing.T) { testGet(t, newRepo(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newRepo(t)) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newRepo(t)) })
+	t.Run("List", func(t *testing.T) { testList(t, newRepo(t)) })
+	t.Run("ListSort", func(t *testing.T) { testListSort(t, newRepo(t)) })
+	t.Run("ListPagination", func(t *testing.T) { testListPagination(t, newRepo(t)) })
+	t.Run("Search", func(t *testing.T) { testSearch(t, newRepo(t)) })
+}
+
+var (
+	ozzy   = person.Person{Name: "Ozzy", LastName: "Osbourne"}
+	ronnie = person.Person{Name: "Ronnie", LastName: "Dio"}
+	tony   = person.Person{Name: "Tony", LastName: "Iommi"}
+)
+
+//seed grava as pessoas no repositório e as retorna com os IDs atribuídos
+func seed(t *testing.T, repo person.Repository, people ...person.Person) []*person.Person {
+	t.Helper()
+	var saved []*person.Person
+	for _, p := range people {
+		p := p
+		id, err := repo.Create(context.Background(), &p)
+		require.Nil(t, err)
+		p.ID = id
+		saved = append(saved, &p)
+	}
+	return saved
+}
+
+func testCreate(t *testing.T, repo person.Repository) {
+	ctx := context.Background()
+	t.Run("atribui ids positivos e crescentes", func(t *testing.T) {
+		id1, err := repo.Create(ctx, &person.Person{Name: "Ozzy", LastName: "Osbourne"})
+		assert.Nil(t, err)
+		id2, err := repo.Create(ctx, &person.Person{Name: "Ronnie", LastName: "Dio"})
+		assert.Nil(t, err)
+		assert.Greater(t, int(id1), 0)
+		assert.Greater(t, int(id2), int(id1))
+	})
+	t.Run("ids não são reaproveitados após remoção", func(t *testing.T) {
+		id1, err := repo.Create(ctx, &person.Person{Name: "Tony", LastName: "Iommi"})
+		require.Nil(t, err)
+		require.Nil(t, repo.Delete(ctx, id1))
+		id2, err := repo.Create(ctx, &person.Person{Name: "Geezer", LastName: "Butler"})
+		assert.Nil(t, err)
+		assert.Greater(t, int(id2), int(id1))
+	})
+}
+
+func testGet(t *testing.T, repo person.Repository) {
+	ctx := context.Background()
+	saved := seed(t, repo, ozzy)
+	t.Run("pessoa encontrada", func(t *testing.T) {
+		found, err := repo.Get(ctx, saved[0].ID)
+		assert.Nil(t, err)
+		assert.Equal(t, saved[0], found)
+	})
+	t.Run("pessoa não encontrada", func(t *testing.T) {
+		found, err := repo.Get(ctx, saved[0].ID+1000)
+		assert.Nil(t, found)
+		assert.ErrorIs(t, err, person.ErrNotFound)
+	})
+}
+
+func testUpdate(t *testing.T, repo person.Repository) {
+	ctx := context.Background()
+	saved := seed(t, repo, ozzy, ronnie)
+	t.Run("altera apenas a pessoa informada", func(t *testing.T) {
+		p := *saved[0]
+		p.Name = "John Michael"
+		err := repo.Update(ctx, &p)
+		assert.Nil(t, err)
+		found, err := repo.Get(ctx, p.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, &p, found)
+		other, err := repo.Get(ctx, saved[1].ID)
+		assert.Nil(t, err)
+		assert.Equal(t, saved[1], other)
+	})
+	t.Run("pessoa não encontrada", func(t *testing.T) {
+		err := repo.Update(ctx, &person.Person{ID: saved[1].ID + 1000, Name: "Tony", LastName: "Iommi"})
+		assert.ErrorIs(t, err, person.ErrNotFound)
+	})
+}
+
+func testDelete(t *testing.T, repo person.Repository) {
+	ctx := context.Background()
+	saved := seed(t, repo, ozzy, ronnie)
+	t.Run("remove a pessoa", func(t *testing.T) {
+		err := repo.Delete(ctx, saved[0].ID)
+		assert.Nil(t, err)
+		found, err := repo.Get(ctx, saved[0].ID)
+		assert.Nil(t, found)
+		assert.ErrorIs(t, err, person.ErrNotFound)
+		other, err := repo.Get(ctx, saved[1].ID)
+		assert.Nil(t, err)
+		assert.Equal(t, saved[1], other)
+	})
+	t.Run("pessoa não encontrada", func(t *testing.T) {
+		err := repo.Delete(ctx, saved[0].ID)
+		assert.ErrorIs(t, err, person.ErrNotFound)
+	})
+}
+
+func testList(t *testing.T, repo person.Repository) {
+	ctx := context.Background()
+	t.Run("repositório vazio", func(t *testing.T) {
+		page, err := repo.List(ctx, person.ListOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, &person.Page{People: []*person.Person{}}, page)
+	})
+	t.Run("ordenado por id por padrão", func(t *testing.T) {
+		saved := seed(t, repo, tony, ozzy, ronnie)
+		page, err := repo.List(ctx, person.ListOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, &person.Page{People: saved, Total: 3}, page)
+	})
+}
+
+func testListSort(t *testing.T, repo person.Repository) {
+	ctx := context.Background()
+	saved := seed(t, repo, ozzy, ronnie, tony)
+	o, r, to := saved[0], saved[1], saved[2]
+	tests := []struct {
+		opts     person.ListOptions
+		expected []*person.Person
+	}{
+		{
+			opts:     person.ListOptions{SortBy: person.SortByID, Desc: true},
+			expected: []*person.Person{to, r, o},
+		},
+		{
+			opts:     person.ListOptions{SortBy: person.SortByName},
+			expected: []*person.Person{o, r, to},
+		},
+		{
+			opts:     person.ListOptions{SortBy: person.SortByName, Desc: true},
+			expected: []*person.Person{to, r, o},
+		},
+		{
+			opts:     person.ListOptions{SortBy: person.SortByLastName},
+			expected: []*person.Person{r, to, o},
+		},
+		{
+			opts:     person.ListOptions{SortBy: person.SortByLastName, Desc: true},
+			expected: []*person.Person{o, to, r},
+		},
+	}
+	for _, test := range tests {
+		page, err := repo.List(ctx, test.opts)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, page.People, "sort %s desc=%v", test.opts.SortBy, test.opts.Desc)
+	}
+}
+
+func testListPagination(t *testing.T, repo person.Repository) {
+	ctx := context.Background()
+	saved := seed(t, repo, ozzy, ronnie, tony)
+	tests := []struct {
+		opts     person.ListOptions
+		expected []*person.Person
+	}{
+		{
+			opts:     person.ListOptions{Limit: 2},
+			expected: saved[:2],
+		},
+		{
+			opts:     person.ListOptions{Limit: 2, Offset: 2},
+			expected: saved[2:],
+		},
+		{
+			opts:     person.ListOptions{Limit: 2, Offset: 10},
+			expected: []*person.Person{},
+		},
+	}
+	for _, test := range tests {
+		page, err := repo.List(ctx, test.opts)
+		assert.Nil(t, err)
+		assert.Equal(t, &person.Page{
+			People: test.expected,
+			Total:  3,
+			Limit:  test.opts.Limit,
+			Offset: test.opts.Offset,
+		}, page)
+	}
+}
+
+func testSearch(t *testing.T, repo person.Repository) {
+	ctx := context.Background()
+	saved := seed(t, repo, ozzy, ronnie, tony)
+	o, r := saved[0], saved[1]
+	tests := []struct {
+		query    string
+		expected []*person.Person
+	}{
+		{query: "ozzy", expected: []*person.Person{o}},
+		{query: "OZZY", expected: []*person.Person{o}},
+		{query: "Osbourne", expected: []*person.Person{o}},
+		{query: "dio", expected: []*person.Person{r}},
+		{query: "onn", expected: []*person.Person{r}},
+		{query: "o", expected: saved},
+	}
+	for _, test := range tests {
+		page, err := repo.Search(ctx, test.query, person.ListOptions{})
+		assert.Nil(t, err, test.query)
+		if assert.NotNil(t, page, test.query) {
+			assert.Equal(t, test.expected, page.People, test.query)
+			assert.Equal(t, len(test.expected), page.Total, test.query)
+		}
+	}
+	t.Run("nenhuma pessoa encontrada", func(t *testing.T) {
+		page, err := repo.Search(ctx, "martin", person.ListOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, &person.Page{People: []*person.Person{}}, page)
+	})
+	t.Run("paginação da busca", func(t *testing.T) {
+		page, err := repo.Search(ctx, "o", person.ListOptions{Limit: 1, Offset: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, &person.Page{People: []*person.Person{r}, Total: 3, Limit: 1, Offset: 1}, page)
+	})
+}
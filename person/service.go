@@ -1,6 +1,7 @@
 package person
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -15,52 +16,68 @@ func NewService(r Repository) *Service {
 	}
 }
 
-func (s *Service) Get(id ID) (*Person, error) {
-	p, err := s.r.Get(id)
+func (s *Service) Get(ctx context.Context, id ID) (*Person, error) {
+	p, err := s.r.Get(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("erro lendo person do repositório: %w", err)
 	}
 	return p, nil
 }
 
-func (s *Service) Search(query string) ([]*Person, error) {
-	p, err := s.r.Search(query)
+func (s *Service) Search(ctx context.Context, query string, opts ListOptions) (*Page, error) {
+	err := opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.r.Search(ctx, query, opts.WithDefaults())
 	if err != nil {
 		return nil, fmt.Errorf("erro buscando person do repositório: %w", err)
 	}
 	return p, nil
 }
 
-func (s *Service) List() ([]*Person, error) {
-	p, err := s.r.List()
+func (s *Service) List(ctx context.Context, opts ListOptions) (*Page, error) {
+	err := opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.r.List(ctx, opts.WithDefaults())
 	if err != nil {
 		return nil, fmt.Errorf("erro listando person do repositório: %w", err)
 	}
 	return p, nil
 }
 
-func (s *Service) Create(firstName, lastName string) (ID, error) {
+func (s *Service) Create(ctx context.Context, firstName, lastName string) (ID, error) {
 	p := Person{
 		Name:     firstName,
 		LastName: lastName,
 	}
-	id, err := s.r.Create(&p)
+	err := p.Validate()
+	if err != nil {
+		return 0, err
+	}
+	id, err := s.r.Create(ctx, &p)
 	if err != nil {
 		return 0, fmt.Errorf("erro criando person no repositório: %w", err)
 	}
 	return id, nil
 }
 
-func (s *Service) Update(e *Person) error {
-	err := s.r.Update(e)
+func (s *Service) Update(ctx context.Context, e *Person) error {
+	err := e.Validate()
+	if err != nil {
+		return err
+	}
+	err = s.r.Update(ctx, e)
 	if err != nil {
 		return fmt.Errorf("erro atualizando person no repositório: %w", err)
 	}
 	return nil
 }
 
-func (s *Service) Delete(id ID) error {
-	err := s.r.Delete(id)
+func (s *Service) Delete(ctx context.Context, id ID) error {
+	err := s.r.Delete(ctx, id)
 	if err != nil {
 		return fmt.Errorf("erro removendo person do repositório: %w", err)
 	}
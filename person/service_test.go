@@ -5,6 +5,7 @@ package person_test
 //boa prática: criar um pacote _test para que sejam testadas as funções públicas do pacote e não as internas
 
 import (
+	"context"
 	"fmt"
 	"github.com/PicPay/go-test-workshop/person"
 	"github.com/PicPay/go-test-workshop/person/mocks"
@@ -13,6 +14,7 @@ import (
 )
 
 func TestService_Get(t *testing.T) {
+	ctx := context.Background()
 	t.Run("usuário encontrado", func(t *testing.T) {
 		//fase: Arrange
 		p := &person.Person{
@@ -21,12 +23,12 @@ func TestService_Get(t *testing.T) {
 			LastName: "Osbourne",
 		}
 		repo := mocks.NewRepository(t)
-		repo.On("Get", person.ID(1)).
+		repo.On("Get", ctx, person.ID(1)).
 			Return(p, nil).
 			Once()
 		service := person.NewService(repo)
 		//fase: Act
-		found, err := service.Get(person.ID(1))
+		found, err := service.Get(ctx, person.ID(1))
 
 		//fase: Assert
 		assert.Nil(t, err)
@@ -35,17 +37,18 @@ func TestService_Get(t *testing.T) {
 	})
 	t.Run("usuário não encontrado", func(t *testing.T) {
 		repo := mocks.NewRepository(t)
-		repo.On("Get", person.ID(1)).
+		repo.On("Get", ctx, person.ID(1)).
 			Return(nil, fmt.Errorf("not found")).
 			Once()
 		service := person.NewService(repo)
-		found, err := service.Get(person.ID(1))
+		found, err := service.Get(ctx, person.ID(1))
 		assert.Nil(t, found)
 		assert.Errorf(t, err, "erro lendo person do repositório: %w")
 	})
 }
 
 func TestService_Search(t *testing.T) {
+	ctx := context.Background()
 	//aqui vamos usar uma técnica chamada Table based tests
 	p1 := &person.Person{
 		ID:       1,
@@ -125,18 +128,98 @@ func TestService_Search(t *testing.T) {
 			mockErr:     fmt.Errorf("not found"),
 		},
 	}
+	opts := person.ListOptions{Limit: person.DefaultLimit, SortBy: person.SortByID}
 	for _, test := range tests {
+		var page *person.Page
+		if test.result != nil {
+			page = &person.Page{People: test.result, Total: len(test.result), Limit: person.DefaultLimit}
+		}
 		repo := mocks.NewRepository(t)
-		repo.On("Search", test.query).
-			Return(test.result, test.mockErr).
+		repo.On("Search", ctx, test.query, opts).
+			Return(page, test.mockErr).
 			Once()
 		service := person.NewService(repo)
-		found, err := service.Search(test.query)
+		found, err := service.Search(ctx, test.query, person.ListOptions{})
 
 		assert.Equal(t, test.expectedErr, err)
-		assert.Equal(t, test.result, found)
+		assert.Equal(t, page, found)
 	}
 
 }
 
+func TestService_Create(t *testing.T) {
+	ctx := context.Background()
+	t.Run("pessoa criada", func(t *testing.T) {
+		repo := mocks.NewRepository(t)
+		repo.On("Create", ctx, &person.Person{Name: "Ozzy", LastName: "Osbourne"}).
+			Return(person.ID(1), nil).
+			Once()
+		service := person.NewService(repo)
+		id, err := service.Create(ctx, "Ozzy", "Osbourne")
+		assert.Nil(t, err)
+		assert.Equal(t, person.ID(1), id)
+	})
+	t.Run("pessoa inválida", func(t *testing.T) {
+		repo := mocks.NewRepository(t)
+		service := person.NewService(repo)
+		id, err := service.Create(ctx, "Ozzy", "")
+		assert.ErrorIs(t, err, person.ErrInvalidPerson)
+		assert.Equal(t, person.ID(0), id)
+	})
+	t.Run("conflito no repositório", func(t *testing.T) {
+		repo := mocks.NewRepository(t)
+		repo.On("Create", ctx, &person.Person{Name: "Ozzy", LastName: "Osbourne"}).
+			Return(person.ID(0), person.ErrConflict).
+			Once()
+		service := person.NewService(repo)
+		_, err := service.Create(ctx, "Ozzy", "Osbourne")
+		assert.ErrorIs(t, err, person.ErrConflict)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+	t.Run("aplica a paginação padrão", func(t *testing.T) {
+		page := &person.Page{
+			People: []*person.Person{{ID: 1, Name: "Ozzy", LastName: "Osbourne"}},
+			Total:  1,
+			Limit:  person.DefaultLimit,
+		}
+		repo := mocks.NewRepository(t)
+		repo.On("List", ctx, person.ListOptions{Limit: person.DefaultLimit, SortBy: person.SortByID}).
+			Return(page, nil).
+			Once()
+		service := person.NewService(repo)
+		found, err := service.List(ctx, person.ListOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, page, found)
+	})
+	t.Run("mantém a paginação informada", func(t *testing.T) {
+		opts := person.ListOptions{Limit: 5, Offset: 10, SortBy: person.SortByLastName, Desc: true}
+		repo := mocks.NewRepository(t)
+		repo.On("List", ctx, opts).
+			Return(&person.Page{People: []*person.Person{}, Total: 3, Limit: 5, Offset: 10}, nil).
+			Once()
+		service := person.NewService(repo)
+		found, err := service.List(ctx, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, found.Total)
+	})
+	t.Run("opções inválidas", func(t *testing.T) {
+		tests := []person.ListOptions{
+			{SortBy: "created_at"},
+			{Limit: -1},
+			{Offset: -1},
+			{Limit: person.MaxLimit + 1},
+		}
+		for _, opts := range tests {
+			repo := mocks.NewRepository(t)
+			service := person.NewService(repo)
+			found, err := service.List(ctx, opts)
+			assert.Nil(t, found)
+			assert.ErrorIs(t, err, person.ErrInvalidListOptions)
+		}
+	})
+}
+
 //para fins didáticos, deixo os demais testes para serem implementados como aprendizado ;)
@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/person"
+	driver "github.com/mattn/go-sqlite3"
+)
+
+//SQLite sqlite repo
+type SQLite struct {
+	db *sql.DB
+}
+
+//NewSQLite create new repository
+func NewSQLite(db *sql.DB) *SQLite {
+	return &SQLite{
+		db: db,
+	}
+}
+
+//Create a person
+func (r *SQLite) Create(ctx context.Context, p *person.Person) (person.ID, error) {
+	stmt, err := r.db.PrepareContext(ctx, `
+		insert into person (first_name, last_name, created_at)
+		values(?,?,?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	res, err := stmt.ExecContext(ctx,
+		p.Name,
+		p.LastName,
+		time.Now().Format("2006-01-02"),
+	)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return person.ID(id), nil
+}
+
+//Get a person
+func (r *SQLite) Get(ctx context.Context, id person.ID) (*person.Person, error) {
+	var p person.Person
+	err := r.db.QueryRowContext(ctx, `select id, first_name, last_name from person where id = ?`, id).
+		Scan(&p.ID, &p.Name, &p.LastName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, person.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+//Update a person
+func (r *SQLite) Update(ctx context.Context, p *person.Person) error {
+	res, err := r.db.ExecContext(ctx, "update person set first_name = ?, last_name = ?, updated_at = ? where id = ?", p.Name, p.LastName, time.Now().Format("2006-01-02"), p.ID)
+	if err != nil {
+		return mapError(err)
+	}
+	return notFoundIfNoRows(res)
+}
+
+//Search person
+func (r *SQLite) Search(ctx context.Context, query string, opts person.ListOptions) (*person.Page, error) {
+	query = "%" + strings.ToLower(query) + "%"
+	return r.page(ctx, "where first_name like ? or last_name like ?", []interface{}{query, query}, opts)
+}
+
+//List person
+func (r *SQLite) List(ctx context.Context, opts person.ListOptions) (*person.Page, error) {
+	return r.page(ctx, "", nil, opts)
+}
+
+//Delete a person
+func (r *SQLite) Delete(ctx context.Context, id person.ID) error {
+	res, err := r.db.ExecContext(ctx, "delete from person where id = ?", id)
+	if err != nil {
+		return err
+	}
+	return notFoundIfNoRows(res)
+}
+
+//page executa a contagem e a consulta paginada com o filtro where
+func (r *SQLite) page(ctx context.Context, where string, args []interface{}, opts person.ListOptions) (*person.Page, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "select count(*) from person "+where, args...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("select id, first_name, last_name from person %s order by %s", where, orderBy(opts))
+	if opts.Limit > 0 {
+		query += " limit ? offset ?"
+		args = append(args, opts.Limit, opts.Offset)
+	}
+	people := []*person.Person{}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p person.Person
+		err = rows.Scan(&p.ID, &p.Name, &p.LastName)
+		if err != nil {
+			return nil, err
+		}
+		people = append(people, &p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &person.Page{
+		People: people,
+		Total:  total,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
+	}, nil
+}
+
+//orderBy monta a cláusula de ordenação. O id é usado como critério de desempate para que a paginação seja estável
+func orderBy(opts person.ListOptions) string {
+	dir := "asc"
+	if opts.Desc {
+		dir = "desc"
+	}
+	switch opts.SortBy {
+	case person.SortByName:
+		return fmt.Sprintf("first_name %s, id %s", dir, dir)
+	case person.SortByLastName:
+		return fmt.Sprintf("last_name %s, id %s", dir, dir)
+	default:
+		return "id " + dir
+	}
+}
+
+//notFoundIfNoRows retorna person.ErrNotFound quando nenhuma linha foi afetada.
+//Diferente do MySQL, o SQLite conta as linhas encontradas e não apenas as alteradas
+func notFoundIfNoRows(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return person.ErrNotFound
+	}
+	return nil
+}
+
+//mapError traduz os erros do driver para os erros do domínio
+func mapError(err error) error {
+	var se driver.Error
+	if errors.As(err, &se) && se.ExtendedCode == driver.ErrConstraintUnique {
+		return fmt.Errorf("%w: %s", person.ErrConflict, se.Error())
+	}
+	return err
+}
@@ -0,0 +1,37 @@
+//go:build unit
+
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/internal/migrations"
+	"github.com/PicPay/go-test-workshop/person"
+	"github.com/PicPay/go-test-workshop/person/repotest"
+	"github.com/PicPay/go-test-workshop/person/sqlite"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//Diferente do MySQL, o SQLite não precisa de um container: cada teste usa um arquivo em um diretório temporário
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	repotest.Run(t, func(t *testing.T) person.Repository {
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "workshop.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		m, err := migrations.New(db, migrations.SQLite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = m.Up(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sqlite.NewSQLite(db)
+	})
+}
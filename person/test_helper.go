@@ -1,83 +0,0 @@
-package person
-
-import (
-	"context"
-	"database/sql"
-	"fmt"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
-)
-
-type MysqlDBContainer struct {
-	testcontainers.Container
-	URI string
-}
-
-const (
-	dbUser         = "workshop"
-	dbPassword     = "workshop"
-	database       = "workshop"
-	dbRootPassword = "db-root-password"
-)
-
-func SetupMysqL(ctx context.Context) (*MysqlDBContainer, error) {
-	req := testcontainers.ContainerRequest{
-		Image:        "mariadb:latest",
-		ExposedPorts: []string{"3306/tcp"},
-		WaitingFor:   wait.ForLog("Version: '10.8.3-MariaDB-1:10.8.3+maria~jammy'  socket: '/run/mysqld/mysqld.sock'  port: 3306  mariadb.org binary distribution"),
-		Env: map[string]string{
-			"MARIADB_USER":          dbUser,
-			"MARIADB_PASSWORD":      dbPassword,
-			"MARIADB_ROOT_PASSWORD": dbRootPassword,
-			"MARIADB_DATABASE":      database,
-		},
-	}
-	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	if err != nil {
-		return nil, err
-	}
-	mappedPort, err := container.MappedPort(ctx, "3306")
-	if err != nil {
-		return nil, err
-	}
-
-	hostIP, err := container.Host(ctx)
-	if err != nil {
-		return nil, err
-	}
-	uri := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", "root", dbRootPassword, hostIP, mappedPort.Port(), database)
-
-	return &MysqlDBContainer{Container: container, URI: uri}, nil
-}
-
-func InitMySQL(ctx context.Context, db *sql.DB) error {
-	query := []string{
-		fmt.Sprintf("use %s;", database),
-		"create table if not exists person (id int AUTO_INCREMENT,first_name varchar(100), last_name varchar(100), created_at datetime, updated_at datetime, PRIMARY KEY (`id`)) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=latin1;",
-	}
-	for _, q := range query {
-		_, err := db.ExecContext(ctx, q)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func TruncateMySQL(ctx context.Context, db *sql.DB) error {
-	query := []string{
-		fmt.Sprintf("use %s;", database),
-		"truncate table person",
-	}
-	for _, q := range query {
-		_, err := db.ExecContext(ctx, q)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
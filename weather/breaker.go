@@ -0,0 +1,169 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	//DefaultFailureThreshold falhas consecutivas que abrem o circuito
+	DefaultFailureThreshold = 5
+	//DefaultOpenTimeout tempo que o circuito fica aberto antes de permitir uma chamada de teste
+	DefaultOpenTimeout = 30 * time.Second
+)
+
+//ErrCircuitOpen é retornado sem chamar a API enquanto o circuito está aberto
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+//BreakerState estado do circuit breaker
+type BreakerState int
+
+const (
+	//StateClosed as chamadas são feitas normalmente
+	StateClosed BreakerState = iota
+	//StateOpen as chamadas falham imediatamente com ErrCircuitOpen
+	StateOpen
+	//StateHalfOpen uma única chamada de teste é permitida para verificar se a API voltou
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+//CircuitBreaker é um HTTPClient que para de chamar a API depois de uma sequência de falhas
+//(erros de conexão, 429 ou 5xx), evitando esperar por timeouts enquanto ela está fora do ar
+type CircuitBreaker struct {
+	next        HTTPClient
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+type BreakerOption func(*CircuitBreaker)
+
+//NewCircuitBreaker cria um CircuitBreaker em volta de next
+func NewCircuitBreaker(next HTTPClient, options ...BreakerOption) *CircuitBreaker {
+	b := &CircuitBreaker{
+		next:        next,
+		threshold:   DefaultFailureThreshold,
+		openTimeout: DefaultOpenTimeout,
+		now:         time.Now,
+	}
+	for _, o := range options {
+		o(b)
+	}
+	return b
+}
+
+//WithFailureThreshold define quantas falhas consecutivas abrem o circuito
+func WithFailureThreshold(n int) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.threshold = n
+	}
+}
+
+//WithOpenTimeout define quanto tempo o circuito fica aberto
+func WithOpenTimeout(d time.Duration) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.openTimeout = d
+	}
+}
+
+//WithBreakerClock substitui o relógio usado pelo circuit breaker. Útil nos testes
+func WithBreakerClock(now func() time.Time) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.now = now
+	}
+}
+
+//State retorna o estado atual do circuito. Pode ser usado nas verificações de saúde da aplicação
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current()
+}
+
+//Check retorna ErrCircuitOpen enquanto o circuito está aberto, ou seja, depois de falhas consecutivas nas chamadas à API.
+//Não faz nenhuma chamada, então pode ser usado nas verificações de saúde sem consumir a cota da API
+func (b *CircuitBreaker) Check(ctx context.Context) error {
+	if b.State() == StateOpen {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) Do(req *http.Request) (*http.Response, error) {
+	err := b.allow()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.next.Do(req)
+	b.record(req, resp, err)
+	return resp, err
+}
+
+//current retorna o estado considerando o tempo que o circuito está aberto. Deve ser chamado com o lock
+func (b *CircuitBreaker) current() BreakerState {
+	if b.state == StateOpen && !b.now().Before(b.openedAt.Add(b.openTimeout)) {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.current() {
+	case StateOpen:
+		return ErrCircuitOpen
+	case StateHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) record(req *http.Request, resp *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err != nil && req.Context().Err() != nil {
+		//a chamada foi cancelada por quem fez a requisição, não é uma falha da API
+		if b.state == StateHalfOpen {
+			b.state = StateOpen
+		}
+		return
+	}
+	if !retryable(resp, err) {
+		b.state = StateClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,99 @@
+package weather_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/PicPay/go-test-workshop/weather/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.openweathermap.org/data/2.5/weather", nil)
+	now := time.Date(2022, 6, 21, 15, 0, 0, 0, time.UTC)
+	newBreaker := func(next weather.HTTPClient) *weather.CircuitBreaker {
+		return weather.NewCircuitBreaker(next,
+			weather.WithFailureThreshold(2),
+			weather.WithOpenTimeout(time.Minute),
+			weather.WithBreakerClock(func() time.Time { return now }),
+		)
+	}
+	t.Run("abre depois das falhas consecutivas", func(t *testing.T) {
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+		next.On("Do", mock.Anything).Return(response(http.StatusInternalServerError, nil), nil).Once()
+		b := newBreaker(next)
+		assert.Equal(t, weather.StateClosed, b.State())
+		assert.Nil(t, b.Check(req.Context()))
+		_, err := b.Do(req)
+		assert.NotNil(t, err)
+		assert.Equal(t, weather.StateClosed, b.State())
+		_, err = b.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, weather.StateOpen, b.State())
+		_, err = b.Do(req)
+		assert.ErrorIs(t, err, weather.ErrCircuitOpen)
+		assert.ErrorIs(t, b.Check(req.Context()), weather.ErrCircuitOpen)
+	})
+	t.Run("sucesso zera as falhas", func(t *testing.T) {
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(response(http.StatusInternalServerError, nil), nil).Once()
+		next.On("Do", mock.Anything).Return(response(http.StatusNotFound, nil), nil).Once()
+		next.On("Do", mock.Anything).Return(response(http.StatusInternalServerError, nil), nil).Once()
+		b := newBreaker(next)
+		for i := 0; i < 3; i++ {
+			_, err := b.Do(req)
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, weather.StateClosed, b.State())
+	})
+	t.Run("fecha quando a chamada de teste funciona", func(t *testing.T) {
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(response(http.StatusServiceUnavailable, nil), nil).Twice()
+		next.On("Do", mock.Anything).Return(response(http.StatusOK, nil), nil).Once()
+		b := newBreaker(next)
+		b.Do(req)
+		b.Do(req)
+		assert.Equal(t, weather.StateOpen, b.State())
+		now = now.Add(time.Minute)
+		assert.Equal(t, weather.StateHalfOpen, b.State())
+		resp, err := b.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, weather.StateClosed, b.State())
+	})
+	t.Run("volta a abrir quando a chamada de teste falha", func(t *testing.T) {
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(response(http.StatusServiceUnavailable, nil), nil).Times(3)
+		b := newBreaker(next)
+		b.Do(req)
+		b.Do(req)
+		now = now.Add(time.Minute)
+		_, err := b.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, weather.StateOpen, b.State())
+		_, err = b.Do(req)
+		assert.ErrorIs(t, err, weather.ErrCircuitOpen)
+	})
+	t.Run("Service retorna ErrUnavailable com o circuito aberto", func(t *testing.T) {
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(nil, errors.New("connection refused")).Twice()
+		s := weather.NewService("fake", weather.WithClient(newBreaker(next)))
+		coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+		s.Get(req.Context(), coord, weather.Options{})
+		s.Get(req.Context(), coord, weather.Options{})
+		_, err := s.Get(req.Context(), coord, weather.Options{})
+		assert.ErrorIs(t, err, weather.ErrUnavailable)
+		assert.ErrorIs(t, err, weather.ErrCircuitOpen)
+	})
+}
+
+func TestBreakerStateString(t *testing.T) {
+	assert.Equal(t, "closed", weather.StateClosed.String())
+	assert.Equal(t, "open", weather.StateOpen.String())
+	assert.Equal(t, "half-open", weather.StateHalfOpen.String())
+}
@@ -0,0 +1,290 @@
+package weather
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//DefaultCacheTTL tempo que uma resposta permanece no cache
+	DefaultCacheTTL = 10 * time.Minute
+	//DefaultCachePrecision casas decimais usadas para agrupar as coordenadas. Duas casas equivalem a aproximadamente 1,1 km
+	DefaultCachePrecision = 2
+	//DefaultCacheSize quantidade máxima de entradas no cache
+	DefaultCacheSize = 1000
+)
+
+//Cache é um UseCase que guarda as respostas de outro UseCase por um tempo, evitando chamadas repetidas à API externa.
+//As coordenadas são arredondadas para uma grade, então consultas próximas compartilham a mesma entrada.
+//Quando o cache está cheio a entrada usada há mais tempo é removida
+type Cache struct {
+	next      UseCase
+	ttl       time.Duration
+	precision int
+	size      int
+	defaults  Options
+	now       func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	lru     *list.List
+	hits    uint64
+	misses  uint64
+}
+
+//cacheKey separa as respostas de cada endpoint e opções. As consultas por coordenada usam coord e as por cidade ou código postal usam query
+type cacheKey struct {
+	endpoint string
+	coord    Coord
+	query    string
+	opts     Options
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	value   interface{}
+	expires time.Time
+}
+
+//CacheStats contadores de acertos e falhas do cache
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+type CacheOption func(*Cache)
+
+//NewCache cria um cache em volta de next
+func NewCache(next UseCase, options ...CacheOption) *Cache {
+	c := &Cache{
+		next:      next,
+		ttl:       DefaultCacheTTL,
+		precision: DefaultCachePrecision,
+		size:      DefaultCacheSize,
+		defaults:  Options{Units: DefaultUnits, Lang: DefaultLang},
+		now:       time.Now,
+		entries:   make(map[cacheKey]*list.Element),
+		lru:       list.New(),
+	}
+	for _, o := range options {
+		o(c)
+	}
+	return c
+}
+
+//WithTTL define por quanto tempo uma resposta é reutilizada
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+//WithPrecision define quantas casas decimais das coordenadas são usadas na chave do cache
+func WithPrecision(decimals int) CacheOption {
+	return func(c *Cache) {
+		c.precision = decimals
+	}
+}
+
+//WithMaxEntries define a quantidade máxima de entradas guardadas
+func WithMaxEntries(size int) CacheOption {
+	return func(c *Cache) {
+		c.size = size
+	}
+}
+
+//WithCacheDefaults informa as unidades e o idioma que o próximo UseCase usa quando a consulta não informa,
+//para que a consulta sem opções e a consulta com os valores padrão compartilhem a mesma entrada
+func WithCacheDefaults(defaults Options) CacheOption {
+	return func(c *Cache) {
+		c.defaults = defaults.withDefaults(c.defaults)
+	}
+}
+
+//WithClock substitui o relógio usado para calcular a expiração. Útil nos testes
+func WithClock(now func() time.Time) CacheOption {
+	return func(c *Cache) {
+		c.now = now
+	}
+}
+
+//Get retorna a resposta guardada para a célula da grade que contém coord ou consulta o próximo UseCase.
+//A consulta é feita com a coordenada arredondada, para que a resposta guardada seja a mesma para toda a célula.
+//Erros não são guardados
+func (c *Cache) Get(ctx context.Context, coord Coord, opts Options) (*Weather, error) {
+	err := coord.Validate()
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey{endpoint: "weather", coord: c.bucket(coord), opts: c.options(opts)}
+	v, err := c.fetch(key, func() (interface{}, error) {
+		return c.next.Get(ctx, key.coord, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyWeather(v.(*Weather)), nil
+}
+
+//ByCity guarda as respostas pela consulta normalizada, sem diferenciar maiúsculas e minúsculas
+func (c *Cache) ByCity(ctx context.Context, query string, opts Options) (*Weather, error) {
+	query, err := CityQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey{endpoint: "city", query: strings.ToLower(query), opts: c.options(opts)}
+	v, err := c.fetch(key, func() (interface{}, error) {
+		return c.next.ByCity(ctx, query, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyWeather(v.(*Weather)), nil
+}
+
+//ByZip guarda as respostas pelo código postal e país
+func (c *Cache) ByZip(ctx context.Context, zip, country string, opts Options) (*Weather, error) {
+	query, err := ZipQuery(zip, country)
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey{endpoint: "zip", query: strings.ToLower(query), opts: c.options(opts)}
+	v, err := c.fetch(key, func() (interface{}, error) {
+		return c.next.ByZip(ctx, zip, country, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyWeather(v.(*Weather)), nil
+}
+
+//Forecast funciona da mesma forma que Get, com entradas separadas para as previsões
+func (c *Cache) Forecast(ctx context.Context, coord Coord, opts Options) (*Forecast, error) {
+	err := coord.Validate()
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey{endpoint: "forecast", coord: c.bucket(coord), opts: c.options(opts)}
+	v, err := c.fetch(key, func() (interface{}, error) {
+		return c.next.Forecast(ctx, key.coord, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyForecast(v.(*Forecast)), nil
+}
+
+//fetch procura a entrada no cache e, se não encontrar, chama get e guarda o resultado.
+//O valor retornado é o mesmo guardado no cache, então quem chama deve devolver uma cópia
+func (c *Cache) fetch(key cacheKey, get func() (interface{}, error)) (interface{}, error) {
+	if v, ok := c.lookup(key); ok {
+		return v, nil
+	}
+	v, err := get()
+	if err != nil {
+		return nil, err
+	}
+	c.store(key, v)
+	return v, nil
+}
+
+//Stats retorna os contadores do cache
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: c.lru.Len(),
+	}
+}
+
+func (c *Cache) lookup(key cacheKey) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if !c.now().Before(e.expires) {
+		c.remove(el)
+		c.misses++
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	c.hits++
+	return e.value, true
+}
+
+func (c *Cache) store(key cacheKey, v interface{}) {
+	if c.ttl <= 0 || c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := &cacheEntry{
+		key:     key,
+		value:   v,
+		expires: c.now().Add(c.ttl),
+	}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
+
+//bucket arredonda as coordenadas para a grade configurada
+func (c *Cache) bucket(coord Coord) Coord {
+	f := math.Pow(10, float64(c.precision))
+	return Coord{
+		Lat: math.Round(coord.Lat*f) / f,
+		Lon: math.Round(coord.Lon*f) / f,
+	}
+}
+
+//options completa as opções com os valores padrão e normaliza o idioma, que a OpenWeather aceita tanto em
+//maiúsculas quanto em minúsculas
+func (c *Cache) options(opts Options) Options {
+	opts = opts.withDefaults(c.defaults)
+	opts.Lang = strings.ToLower(opts.Lang)
+	return opts
+}
+
+//copyWeather evita que quem recebe a resposta altere o valor guardado no cache
+func copyWeather(w *Weather) *Weather {
+	if w == nil {
+		return nil
+	}
+	cp := *w
+	cp.Conditions = append([]Condition(nil), w.Conditions...)
+	return &cp
+}
+
+func copyForecast(f *Forecast) *Forecast {
+	if f == nil {
+		return nil
+	}
+	cp := *f
+	cp.List = append([]ForecastItem(nil), f.List...)
+	for i := range cp.List {
+		cp.List[i].Conditions = append([]Condition(nil), cp.List[i].Conditions...)
+	}
+	return &cp
+}
@@ -0,0 +1,163 @@
+package weather_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/PicPay/go-test-workshop/weather/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	floripa := &weather.Weather{Coord: weather.Coord{Lat: -27.6, Lon: -48.55}, Name: "Florianópolis"}
+	t.Run("coordenadas próximas usam a mesma entrada", func(t *testing.T) {
+		next := mocks.NewUseCase(t)
+		next.On("Get", ctx, weather.Coord{Lat: -27.6, Lon: -48.55}, weather.Options{}).
+			Return(floripa, nil).
+			Once()
+		c := weather.NewCache(next)
+		w, err := c.Get(ctx, weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, floripa, w)
+		w, err = c.Get(ctx, weather.Coord{Lat: -27.6012, Lon: -48.5521}, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, floripa, w)
+		assert.Equal(t, weather.CacheStats{Hits: 1, Misses: 1, Entries: 1}, c.Stats())
+	})
+	t.Run("entrada expira após o ttl", func(t *testing.T) {
+		now := time.Date(2022, 6, 21, 15, 0, 0, 0, time.UTC)
+		next := mocks.NewUseCase(t)
+		next.On("Get", ctx, weather.Coord{Lat: -27.6, Lon: -48.55}, weather.Options{}).
+			Return(floripa, nil).
+			Twice()
+		c := weather.NewCache(next,
+			weather.WithTTL(time.Minute),
+			weather.WithClock(func() time.Time { return now }),
+		)
+		_, err := c.Get(ctx, floripa.Coord, weather.Options{})
+		assert.Nil(t, err)
+		now = now.Add(59 * time.Second)
+		_, err = c.Get(ctx, floripa.Coord, weather.Options{})
+		assert.Nil(t, err)
+		now = now.Add(time.Second)
+		_, err = c.Get(ctx, floripa.Coord, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, weather.CacheStats{Hits: 1, Misses: 2, Entries: 1}, c.Stats())
+	})
+	t.Run("remove a entrada usada há mais tempo", func(t *testing.T) {
+		a := weather.Coord{Lat: 1, Lon: 1}
+		b := weather.Coord{Lat: 2, Lon: 2}
+		d := weather.Coord{Lat: 3, Lon: 3}
+		next := mocks.NewUseCase(t)
+		next.On("Get", ctx, a, weather.Options{}).Return(&weather.Weather{Name: "a"}, nil).Once()
+		next.On("Get", ctx, b, weather.Options{}).Return(&weather.Weather{Name: "b"}, nil).Twice()
+		next.On("Get", ctx, d, weather.Options{}).Return(&weather.Weather{Name: "d"}, nil).Once()
+		c := weather.NewCache(next, weather.WithMaxEntries(2))
+		for _, coord := range []weather.Coord{a, b, a, d, a, b} {
+			_, err := c.Get(ctx, coord, weather.Options{})
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, weather.CacheStats{Hits: 2, Misses: 4, Entries: 2}, c.Stats())
+	})
+	t.Run("erros não são guardados", func(t *testing.T) {
+		next := mocks.NewUseCase(t)
+		next.On("Get", ctx, floripa.Coord, weather.Options{}).
+			Return(nil, &weather.UpstreamError{Kind: weather.ErrUnavailable, StatusCode: 503}).
+			Once()
+		next.On("Get", ctx, floripa.Coord, weather.Options{}).
+			Return(floripa, nil).
+			Once()
+		c := weather.NewCache(next)
+		_, err := c.Get(ctx, floripa.Coord, weather.Options{})
+		assert.ErrorIs(t, err, weather.ErrUnavailable)
+		w, err := c.Get(ctx, floripa.Coord, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, floripa, w)
+	})
+	t.Run("alterar a resposta não altera o cache", func(t *testing.T) {
+		next := mocks.NewUseCase(t)
+		next.On("Get", ctx, floripa.Coord, weather.Options{}).
+			Return(&weather.Weather{Name: "Florianópolis"}, nil).
+			Once()
+		c := weather.NewCache(next)
+		w, err := c.Get(ctx, floripa.Coord, weather.Options{})
+		assert.Nil(t, err)
+		w.Name = "alterado"
+		w, err = c.Get(ctx, floripa.Coord, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, "Florianópolis", w.Name)
+	})
+	t.Run("previsões usam entradas separadas", func(t *testing.T) {
+		forecast := &weather.Forecast{
+			City: weather.City{Name: "Florianópolis"},
+			List: []weather.ForecastItem{{
+				Time:       time.Unix(1655845200, 0).UTC(),
+				Conditions: []weather.Condition{{ID: 500, Main: "Rain"}},
+			}},
+		}
+		next := mocks.NewUseCase(t)
+		next.On("Get", ctx, floripa.Coord, weather.Options{}).Return(floripa, nil).Once()
+		next.On("Forecast", ctx, floripa.Coord, weather.Options{}).Return(forecast, nil).Once()
+		c := weather.NewCache(next)
+		w, err := c.Get(ctx, floripa.Coord, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, floripa, w)
+		f, err := c.Forecast(ctx, floripa.Coord, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, forecast, f)
+		f.List[0].Time = time.Time{}
+		f.List[0].Conditions[0].Main = "alterado"
+		f, err = c.Forecast(ctx, floripa.Coord, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, forecast, f)
+		assert.Equal(t, weather.CacheStats{Hits: 1, Misses: 2, Entries: 2}, c.Stats())
+	})
+	t.Run("consultas por cidade e código postal", func(t *testing.T) {
+		next := mocks.NewUseCase(t)
+		next.On("ByCity", ctx, "Florianópolis,BR", weather.Options{}).Return(floripa, nil).Once()
+		next.On("ByZip", ctx, "88010-000", "BR", weather.Options{}).Return(floripa, nil).Once()
+		c := weather.NewCache(next)
+		for _, q := range []string{"Florianópolis,BR", "florianópolis,br", " FLORIANÓPOLIS,BR"} {
+			w, err := c.ByCity(ctx, q, weather.Options{})
+			assert.Nil(t, err)
+			assert.Equal(t, floripa, w)
+		}
+		for _, country := range []string{"BR", "br"} {
+			w, err := c.ByZip(ctx, "88010-000", country, weather.Options{})
+			assert.Nil(t, err)
+			assert.Equal(t, floripa, w)
+		}
+		assert.Equal(t, weather.CacheStats{Hits: 3, Misses: 2, Entries: 2}, c.Stats())
+	})
+	t.Run("opções diferentes usam entradas separadas", func(t *testing.T) {
+		imperial := weather.Options{Units: weather.UnitsImperial, Lang: "en"}
+		next := mocks.NewUseCase(t)
+		next.On("Get", ctx, floripa.Coord, weather.Options{}).Return(floripa, nil).Once()
+		next.On("Get", ctx, floripa.Coord, imperial).Return(floripa, nil).Once()
+		c := weather.NewCache(next)
+		for _, opts := range []weather.Options{{}, imperial, {}, {Units: weather.UnitsImperial, Lang: "EN"}} {
+			_, err := c.Get(ctx, floripa.Coord, opts)
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, weather.CacheStats{Hits: 2, Misses: 2, Entries: 2}, c.Stats())
+	})
+	t.Run("opções omitidas usam a mesma entrada dos valores padrão", func(t *testing.T) {
+		next := mocks.NewUseCase(t)
+		next.On("Get", ctx, floripa.Coord, weather.Options{}).Return(floripa, nil).Once()
+		c := weather.NewCache(next, weather.WithCacheDefaults(weather.Options{Units: weather.UnitsImperial, Lang: "en"}))
+		for _, opts := range []weather.Options{{}, {Units: weather.UnitsImperial}, {Lang: "EN"}, {Units: weather.UnitsImperial, Lang: "en"}} {
+			_, err := c.Get(ctx, floripa.Coord, opts)
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, weather.CacheStats{Hits: 3, Misses: 1, Entries: 1}, c.Stats())
+	})
+	t.Run("coordenadas inválidas", func(t *testing.T) {
+		c := weather.NewCache(mocks.NewUseCase(t))
+		_, err := c.Get(ctx, weather.Coord{Lat: 100}, weather.Options{})
+		assert.ErrorIs(t, err, weather.ErrInvalidCoord)
+		assert.Equal(t, weather.CacheStats{}, c.Stats())
+	})
+}
@@ -0,0 +1,40 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+//ErrInvalidCoord é retornado quando a latitude ou a longitude não são números válidos ou estão fora do intervalo
+var ErrInvalidCoord = errors.New("invalid coordinates")
+
+//ParseCoord converte a latitude e a longitude recebidas como texto, por exemplo nos parâmetros da URL, e valida os valores
+func ParseCoord(lat, long string) (Coord, error) {
+	latitude, err := strconv.ParseFloat(lat, 64)
+	if err != nil {
+		return Coord{}, fmt.Errorf("%w: latitude %q is not a number", ErrInvalidCoord, lat)
+	}
+	longitude, err := strconv.ParseFloat(long, 64)
+	if err != nil {
+		return Coord{}, fmt.Errorf("%w: longitude %q is not a number", ErrInvalidCoord, long)
+	}
+	c := Coord{Lat: latitude, Lon: longitude}
+	err = c.Validate()
+	if err != nil {
+		return Coord{}, err
+	}
+	return c, nil
+}
+
+//Validate verifica se a latitude está entre -90 e 90 e a longitude entre -180 e 180.
+//As comparações são escritas de forma que NaN também seja recusado
+func (c Coord) Validate() error {
+	if !(c.Lat >= -90 && c.Lat <= 90) {
+		return fmt.Errorf("%w: latitude must be between -90 and 90", ErrInvalidCoord)
+	}
+	if !(c.Lon >= -180 && c.Lon <= 180) {
+		return fmt.Errorf("%w: longitude must be between -180 and 180", ErrInvalidCoord)
+	}
+	return nil
+}
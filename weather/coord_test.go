@@ -0,0 +1,48 @@
+package weather_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCoord(t *testing.T) {
+	t.Run("coordenadas válidas", func(t *testing.T) {
+		c, err := weather.ParseCoord("-27.5969", "-48.5495")
+		assert.Nil(t, err)
+		assert.Equal(t, weather.Coord{Lat: -27.5969, Lon: -48.5495}, c)
+	})
+	t.Run("limites", func(t *testing.T) {
+		c, err := weather.ParseCoord("90", "-180")
+		assert.Nil(t, err)
+		assert.Equal(t, weather.Coord{Lat: 90, Lon: -180}, c)
+	})
+	invalid := []struct {
+		name string
+		lat  string
+		long string
+	}{
+		{"latitude não numérica", "abc", "-48.5495"},
+		{"longitude não numérica", "-27.5969", "-48.5495&appid=outra"},
+		{"latitude fora do intervalo", "90.1", "-48.5495"},
+		{"longitude fora do intervalo", "-27.5969", "180.5"},
+		{"NaN", "NaN", "-48.5495"},
+		{"infinito", "-27.5969", "Inf"},
+		{"vazio", "", ""},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := weather.ParseCoord(tt.lat, tt.long)
+			assert.ErrorIs(t, err, weather.ErrInvalidCoord)
+		})
+	}
+}
+
+func TestCoordValidate(t *testing.T) {
+	assert.Nil(t, weather.Coord{Lat: 0, Lon: 0}.Validate())
+	assert.ErrorIs(t, weather.Coord{Lat: -91, Lon: 0}.Validate(), weather.ErrInvalidCoord)
+	assert.ErrorIs(t, weather.Coord{Lat: 0, Lon: 181}.Validate(), weather.ErrInvalidCoord)
+	assert.ErrorIs(t, weather.Coord{Lat: math.NaN(), Lon: 0}.Validate(), weather.ErrInvalidCoord)
+}
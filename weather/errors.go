@@ -0,0 +1,76 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+Classificação das falhas da API de previsão do tempo. O Service retorna um *UpstreamError,
+que pode ser comparado com estes erros usando errors.Is
+*/
+var (
+	//ErrUnauthorized a chave da API foi recusada (401)
+	ErrUnauthorized = errors.New("weather api: unauthorized")
+	//ErrBadRequest os parâmetros enviados foram recusados (400)
+	ErrBadRequest = errors.New("weather api: bad request")
+	//ErrNotFound a localização não foi encontrada (404)
+	ErrNotFound = errors.New("weather api: not found")
+	//ErrRateLimited o limite de requisições foi atingido (429)
+	ErrRateLimited = errors.New("weather api: rate limited")
+	//ErrUnavailable a API está fora do ar, retornou 5xx ou não respondeu
+	ErrUnavailable = errors.New("weather api: unavailable")
+	//ErrUnexpected a API retornou um status não esperado
+	ErrUnexpected = errors.New("weather api: unexpected response")
+	//ErrUnsupported o provedor não oferece a consulta, como a busca por código postal na Open-Meteo
+	ErrUnsupported = errors.New("weather api: not supported by provider")
+)
+
+//UpstreamError representa uma falha na chamada à API de previsão do tempo
+type UpstreamError struct {
+	//Kind é um dos erros acima
+	Kind error
+	//StatusCode é o status retornado pela API, ou zero se não houve resposta
+	StatusCode int
+	//Message é a mensagem de erro retornada pela API
+	Message string
+	//Err é a causa quando não houve resposta (timeout, conexão recusada, contexto cancelado...)
+	Err error
+}
+
+func (e *UpstreamError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("%s: %s", e.Kind, e.Err)
+	case e.Message != "":
+		return fmt.Sprintf("%s: %d %s", e.Kind, e.StatusCode, e.Message)
+	default:
+		return fmt.Sprintf("%s: %d", e.Kind, e.StatusCode)
+	}
+}
+
+func (e *UpstreamError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+//classify converte o status HTTP da resposta no tipo de erro correspondente
+func classify(statusCode int) error {
+	switch {
+	case statusCode == 400:
+		return ErrBadRequest
+	case statusCode == 401:
+		return ErrUnauthorized
+	case statusCode == 404:
+		return ErrNotFound
+	case statusCode == 429:
+		return ErrRateLimited
+	case statusCode >= 500:
+		return ErrUnavailable
+	default:
+		return ErrUnexpected
+	}
+}
@@ -0,0 +1,318 @@
+//Package fake simula a API da OpenWeather para testes e para executar a aplicação sem acesso à internet.
+//As respostas são configuradas por coordenada, cidade ou código postal, e é possível injetar erros e latência
+//e consultar as requisições recebidas
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/weather"
+)
+
+//BasePath é o caminho da API atendido pelo servidor, o mesmo da OpenWeather
+const BasePath = "/data/2.5"
+
+//Request é uma requisição recebida pelo servidor
+type Request struct {
+	Path  string
+	Query url.Values
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+//OpenWeather é um http.Handler que responde como os endpoints /weather e /forecast da OpenWeather.
+//As respostas são enviadas como configuradas, independente das unidades e do idioma pedidos
+type OpenWeather struct {
+	mu        sync.Mutex
+	apiKey    string
+	latency   time.Duration
+	fallback  *weather.Static
+	current   map[weather.Coord]weather.Weather
+	cities    map[string]weather.Weather
+	zips      map[string]weather.Weather
+	forecasts map[weather.Coord]weather.Forecast
+	failure   *failure
+	failNext  []failure
+	requests  []Request
+}
+
+type Option func(*OpenWeather)
+
+//New cria o handler. Coordenadas sem resposta configurada recebem as condições de weather.NewStatic,
+//enquanto cidades e códigos postais não configurados recebem 404, como na OpenWeather
+func New(options ...Option) *OpenWeather {
+	o := &OpenWeather{
+		fallback:  weather.NewStatic(),
+		current:   make(map[weather.Coord]weather.Weather),
+		cities:    make(map[string]weather.Weather),
+		zips:      make(map[string]weather.Weather),
+		forecasts: make(map[weather.Coord]weather.Forecast),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+//WithAPIKey faz o servidor responder 401 quando o parâmetro appid é diferente de key. Sem esta opção qualquer chave é aceita
+func WithAPIKey(key string) Option {
+	return func(o *OpenWeather) {
+		o.apiKey = key
+	}
+}
+
+//WithLatency atrasa todas as respostas em d
+func WithLatency(d time.Duration) Option {
+	return func(o *OpenWeather) {
+		o.latency = d
+	}
+}
+
+//WithDefault define as condições e a previsão das coordenadas sem resposta configurada
+func WithDefault(w weather.Weather, f weather.Forecast) Option {
+	return func(o *OpenWeather) {
+		o.fallback = weather.NewStatic(weather.WithStaticWeather(w), weather.WithStaticForecast(f))
+	}
+}
+
+//SetWeather define as condições atuais retornadas para as coordenadas c
+func (o *OpenWeather) SetWeather(c weather.Coord, w weather.Weather) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.current[c] = w
+}
+
+//SetCity define as condições atuais retornadas para a consulta por cidade, como "Florianópolis" ou "Florianópolis,BR".
+//A busca não diferencia maiúsculas de minúsculas, e "Florianópolis,BR" também é encontrada por "Florianópolis"
+func (o *OpenWeather) SetCity(query string, w weather.Weather) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.cities[cityKey(query)] = w
+	if i := strings.Index(query, ","); i > 0 {
+		o.cities[cityKey(query[:i])] = w
+	}
+}
+
+//SetZip define as condições atuais retornadas para o código postal do país informado
+func (o *OpenWeather) SetZip(zip, country string, w weather.Weather) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.zips[zipKey(zip, country)] = w
+}
+
+//SetForecast define a previsão retornada para as coordenadas c
+func (o *OpenWeather) SetForecast(c weather.Coord, f weather.Forecast) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.forecasts[c] = f
+}
+
+//SetLatency altera o atraso das próximas respostas
+func (o *OpenWeather) SetLatency(d time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.latency = d
+}
+
+//Fail faz todas as próximas requisições falharem com o status e a mensagem informados, até que Recover seja chamado
+func (o *OpenWeather) Fail(status int, message string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.failure = &failure{status: status, message: message}
+}
+
+//FailNext faz as próximas n requisições falharem com o status e a mensagem informados
+func (o *OpenWeather) FailNext(n, status int, message string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i := 0; i < n; i++ {
+		o.failNext = append(o.failNext, failure{status: status, message: message})
+	}
+}
+
+//Recover remove os erros injetados por Fail e FailNext
+func (o *OpenWeather) Recover() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.failure = nil
+	o.failNext = nil
+}
+
+//Requests retorna as requisições recebidas, na ordem em que chegaram
+func (o *OpenWeather) Requests() []Request {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]Request(nil), o.requests...)
+}
+
+func (o *OpenWeather) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	latency, fail := o.record(r)
+	if latency > 0 {
+		t := time.NewTimer(latency)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if fail != nil {
+		writeError(w, fail.status, fail.message)
+		return
+	}
+	if o.apiKey != "" && r.URL.Query().Get("appid") != o.apiKey {
+		writeError(w, http.StatusUnauthorized, "Invalid API key. Please see https://openweathermap.org/faq#error401 for more info.")
+		return
+	}
+	switch r.URL.Path {
+	case BasePath + "/weather":
+		o.serveWeather(w, r)
+	case BasePath + "/forecast":
+		o.serveForecast(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "Internal error")
+	}
+}
+
+//record registra a requisição e retorna a latência e o erro injetado que devem ser aplicados a ela
+func (o *OpenWeather) record(r *http.Request) (time.Duration, *failure) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.requests = append(o.requests, Request{Path: r.URL.Path, Query: r.URL.Query()})
+	if len(o.failNext) > 0 {
+		f := o.failNext[0]
+		o.failNext = o.failNext[1:]
+		return o.latency, &f
+	}
+	return o.latency, o.failure
+}
+
+func (o *OpenWeather) serveWeather(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Has("q") {
+		o.serveLookup(w, o.cities, cityKey(q.Get("q")))
+		return
+	}
+	if q.Has("zip") {
+		zip := strings.SplitN(q.Get("zip"), ",", 2)
+		if len(zip) == 1 {
+			zip = append(zip, "US")
+		}
+		o.serveLookup(w, o.zips, zipKey(zip[0], zip[1]))
+		return
+	}
+	c, ok := coord(w, q)
+	if !ok {
+		return
+	}
+	o.mu.Lock()
+	current, ok := o.current[c]
+	o.mu.Unlock()
+	if !ok {
+		d, err := o.fallback.Get(r.Context(), c, weather.Options{})
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		current = *d
+	}
+	writeJSON(w, currentResponse(current))
+}
+
+func (o *OpenWeather) serveLookup(w http.ResponseWriter, m map[string]weather.Weather, key string) {
+	o.mu.Lock()
+	current, ok := m[key]
+	o.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "city not found")
+		return
+	}
+	writeJSON(w, currentResponse(current))
+}
+
+func (o *OpenWeather) serveForecast(w http.ResponseWriter, r *http.Request) {
+	c, ok := coord(w, r.URL.Query())
+	if !ok {
+		return
+	}
+	o.mu.Lock()
+	f, ok := o.forecasts[c]
+	o.mu.Unlock()
+	if !ok {
+		d, err := o.fallback.Forecast(r.Context(), c, weather.Options{})
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		f = *d
+	}
+	writeJSON(w, forecastResponse(f))
+}
+
+//coord lê as coordenadas da consulta. Se forem inválidas responde 400, como a OpenWeather, e retorna false
+func coord(w http.ResponseWriter, q url.Values) (weather.Coord, bool) {
+	lat, err := strconv.ParseFloat(q.Get("lat"), 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "wrong latitude")
+		return weather.Coord{}, false
+	}
+	lon, err := strconv.ParseFloat(q.Get("lon"), 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "wrong longitude")
+		return weather.Coord{}, false
+	}
+	return weather.Coord{Lat: lat, Lon: lon}, true
+}
+
+func cityKey(query string) string {
+	return strings.ToLower(strings.TrimSpace(query))
+}
+
+func zipKey(zip, country string) string {
+	return strings.TrimSpace(zip) + "," + strings.ToUpper(strings.TrimSpace(country))
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(v)
+}
+
+//writeError responde no formato de erro da OpenWeather
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"cod":     strconv.Itoa(status),
+		"message": message,
+	})
+}
+
+//Server é um OpenWeather servido por um httptest.Server
+type Server struct {
+	*OpenWeather
+	*httptest.Server
+}
+
+//NewServer inicia o servidor. Ele deve ser encerrado com Close
+func NewServer(options ...Option) *Server {
+	o := New(options...)
+	return &Server{
+		OpenWeather: o,
+		Server:      httptest.NewServer(o),
+	}
+}
+
+//BaseURL retorna a URL a ser usada em weather.WithBaseURL
+func (s *Server) BaseURL() string {
+	return s.URL + BasePath
+}
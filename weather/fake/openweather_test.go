@@ -0,0 +1,158 @@
+package fake_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/PicPay/go-test-workshop/weather/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenWeather(t *testing.T) {
+	ctx := context.Background()
+	brt := time.FixedZone("", -3*60*60)
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+	floripa := weather.Weather{
+		ID:    3463237,
+		Name:  "Florianópolis",
+		Coord: coord,
+		Conditions: []weather.Condition{
+			{ID: 211, Main: "Thunderstorm", Description: "trovoadas", Icon: "11d"},
+		},
+		Main:       weather.Main{Temp: 19.69, FeelsLike: 20.2, TempMin: 15.99, TempMax: 20.96, Pressure: 1013, Humidity: 95},
+		Wind:       weather.Wind{Speed: 2.57, Deg: 90},
+		Clouds:     weather.Clouds{All: 75},
+		Visibility: 10000,
+		Time:       time.Date(2022, 6, 21, 15, 34, 16, 0, brt),
+		Sys: weather.Sys{
+			Country: "BR",
+			Sunrise: time.Date(2022, 6, 21, 7, 4, 10, 0, brt),
+			Sunset:  time.Date(2022, 6, 21, 17, 27, 44, 0, brt),
+		},
+		Timezone: -10800,
+		Units:    weather.UnitsMetric,
+	}
+	server := fake.NewServer(fake.WithAPIKey("fake"))
+	defer server.Close()
+	server.SetWeather(coord, floripa)
+	server.SetCity("Florianópolis,BR", floripa)
+	server.SetZip("88010-000", "BR", floripa)
+	s := weather.NewService("fake", weather.WithBaseURL(server.BaseURL()))
+
+	t.Run("coordenadas configuradas", func(t *testing.T) {
+		w, err := s.Get(ctx, coord, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, &floripa, w)
+	})
+	t.Run("coordenadas sem resposta configurada", func(t *testing.T) {
+		w, err := s.Get(ctx, weather.Coord{Lat: 10, Lon: 20}, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, weather.Coord{Lat: 10, Lon: 20}, w.Coord)
+		assert.NotEmpty(t, w.Conditions)
+	})
+	t.Run("cidade", func(t *testing.T) {
+		w, err := s.ByCity(ctx, "florianópolis", weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, "Florianópolis", w.Name)
+		_, err = s.ByCity(ctx, "Atlantida", weather.Options{})
+		assert.ErrorIs(t, err, weather.ErrNotFound)
+	})
+	t.Run("código postal", func(t *testing.T) {
+		w, err := s.ByZip(ctx, "88010-000", "br", weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, "Florianópolis", w.Name)
+		_, err = s.ByZip(ctx, "88010-000", "", weather.Options{})
+		assert.ErrorIs(t, err, weather.ErrNotFound)
+	})
+	t.Run("previsão", func(t *testing.T) {
+		brt := time.FixedZone("", -3*60*60)
+		item := weather.ForecastItem{
+			Time:       time.Date(2022, 6, 21, 18, 0, 0, 0, brt),
+			Conditions: []weather.Condition{{ID: 500, Main: "Rain", Description: "chuva leve", Icon: "10n"}},
+			Main:       weather.Main{Temp: 18.74, Humidity: 93},
+			Wind:       weather.Wind{Speed: 3.09, Deg: 57},
+			Clouds:     weather.Clouds{All: 100},
+			Visibility: 10000,
+		}
+		server.SetForecast(coord, weather.Forecast{
+			City:     weather.City{Name: "Florianópolis", Country: "BR", Coord: coord},
+			List:     []weather.ForecastItem{item},
+			Timezone: -10800,
+		})
+		f, err := s.Forecast(ctx, coord, weather.Options{Units: weather.UnitsImperial})
+		assert.Nil(t, err)
+		assert.Equal(t, "Florianópolis", f.City.Name)
+		assert.Equal(t, []weather.ForecastItem{item}, f.List)
+		assert.Equal(t, -10800, f.Timezone)
+		assert.Equal(t, weather.UnitsImperial, f.Units)
+	})
+	t.Run("chave inválida", func(t *testing.T) {
+		_, err := weather.NewService("other", weather.WithBaseURL(server.BaseURL())).Get(ctx, coord, weather.Options{})
+		assert.ErrorIs(t, err, weather.ErrUnauthorized)
+	})
+}
+
+func TestOpenWeatherRequests(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	s := weather.NewService("fake", weather.WithBaseURL(server.BaseURL()+"/"))
+	_, err := s.Get(context.Background(), weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{Units: weather.UnitsImperial, Lang: "en"})
+	assert.Nil(t, err)
+	requests := server.Requests()
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "/data/2.5/weather", requests[0].Path)
+	assert.Equal(t, "-27.5969", requests[0].Query.Get("lat"))
+	assert.Equal(t, "imperial", requests[0].Query.Get("units"))
+	assert.Equal(t, "en", requests[0].Query.Get("lang"))
+	assert.Equal(t, "fake", requests[0].Query.Get("appid"))
+}
+
+func TestOpenWeatherErrors(t *testing.T) {
+	ctx := context.Background()
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+	server := fake.NewServer()
+	defer server.Close()
+	s := weather.NewService("fake", weather.WithBaseURL(server.BaseURL()))
+	t.Run("próximas requisições", func(t *testing.T) {
+		server.FailNext(1, http.StatusTooManyRequests, "rate limit")
+		_, err := s.Get(ctx, coord, weather.Options{})
+		assert.ErrorIs(t, err, weather.ErrRateLimited)
+		var ue *weather.UpstreamError
+		assert.ErrorAs(t, err, &ue)
+		assert.Equal(t, "rate limit", ue.Message)
+		_, err = s.Get(ctx, coord, weather.Options{})
+		assert.Nil(t, err)
+	})
+	t.Run("até recuperar", func(t *testing.T) {
+		server.Fail(http.StatusServiceUnavailable, "")
+		for i := 0; i < 2; i++ {
+			_, err := s.Get(ctx, coord, weather.Options{})
+			assert.ErrorIs(t, err, weather.ErrUnavailable)
+		}
+		server.Recover()
+		_, err := s.Get(ctx, coord, weather.Options{})
+		assert.Nil(t, err)
+	})
+	t.Run("novas tentativas", func(t *testing.T) {
+		server.FailNext(2, http.StatusBadGateway, "")
+		client := weather.NewRetryClient(http.DefaultClient, weather.WithBackoff(time.Millisecond, time.Millisecond))
+		s := weather.NewService("fake", weather.WithBaseURL(server.BaseURL()), weather.WithClient(client))
+		requests := len(server.Requests())
+		_, err := s.Get(ctx, coord, weather.Options{})
+		assert.Nil(t, err)
+		assert.Len(t, server.Requests(), requests+3)
+	})
+	t.Run("latência maior que o timeout", func(t *testing.T) {
+		server.SetLatency(time.Second)
+		defer server.SetLatency(0)
+		s := weather.NewService("fake",
+			weather.WithBaseURL(server.BaseURL()),
+			weather.WithClient(&http.Client{Timeout: 50 * time.Millisecond}),
+		)
+		_, err := s.Get(ctx, coord, weather.Options{})
+		assert.ErrorIs(t, err, weather.ErrUnavailable)
+	})
+}
@@ -0,0 +1,113 @@
+package fake
+
+import (
+	"time"
+
+	"github.com/PicPay/go-test-workshop/weather"
+)
+
+//currentJSON é o formato da resposta de /data/2.5/weather da OpenWeather
+type currentJSON struct {
+	ID         int64               `json:"id"`
+	Name       string              `json:"name"`
+	Coord      weather.Coord       `json:"coord"`
+	Weather    []weather.Condition `json:"weather"`
+	Base       string              `json:"base"`
+	Main       weather.Main        `json:"main"`
+	Wind       weather.Wind        `json:"wind"`
+	Clouds     weather.Clouds      `json:"clouds"`
+	Visibility int64               `json:"visibility"`
+	Dt         int64               `json:"dt"`
+	Sys        sys                 `json:"sys"`
+	Timezone   int                 `json:"timezone"`
+	Cod        int                 `json:"cod"`
+}
+
+type sys struct {
+	Country string `json:"country"`
+	Sunrise int64  `json:"sunrise"`
+	Sunset  int64  `json:"sunset"`
+}
+
+//currentResponse converte o modelo Weather para o formato da OpenWeather, com os horários como unix timestamps
+func currentResponse(w weather.Weather) currentJSON {
+	return currentJSON{
+		ID:         w.ID,
+		Name:       w.Name,
+		Coord:      w.Coord,
+		Weather:    w.Conditions,
+		Base:       "stations",
+		Main:       w.Main,
+		Wind:       w.Wind,
+		Clouds:     w.Clouds,
+		Visibility: w.Visibility,
+		Dt:         unix(w.Time),
+		Sys: sys{
+			Country: w.Sys.Country,
+			Sunrise: unix(w.Sys.Sunrise),
+			Sunset:  unix(w.Sys.Sunset),
+		},
+		Timezone: w.Timezone,
+		Cod:      200,
+	}
+}
+
+//unix converte o horário para unix timestamp. O horário zero é enviado como 0, que a OpenWeather usa para campos ausentes
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+//forecastJSON é o formato da resposta de /data/2.5/forecast da OpenWeather
+type forecastJSON struct {
+	Cod  string         `json:"cod"`
+	Cnt  int            `json:"cnt"`
+	List []forecastItem `json:"list"`
+	City city           `json:"city"`
+}
+
+type forecastItem struct {
+	Dt         int64               `json:"dt"`
+	Main       weather.Main        `json:"main"`
+	Weather    []weather.Condition `json:"weather"`
+	Clouds     weather.Clouds      `json:"clouds"`
+	Wind       weather.Wind        `json:"wind"`
+	Visibility int64               `json:"visibility"`
+	DtTxt      string              `json:"dt_txt"`
+}
+
+type city struct {
+	Name     string        `json:"name"`
+	Coord    weather.Coord `json:"coord"`
+	Country  string        `json:"country"`
+	Timezone int           `json:"timezone"`
+}
+
+//forecastResponse converte o modelo Forecast para o formato da OpenWeather. dt_txt é enviado em UTC, como na API
+func forecastResponse(f weather.Forecast) forecastJSON {
+	r := forecastJSON{
+		Cod:  "200",
+		Cnt:  len(f.List),
+		List: make([]forecastItem, 0, len(f.List)),
+		City: city{
+			Name:     f.City.Name,
+			Coord:    f.City.Coord,
+			Country:  f.City.Country,
+			Timezone: f.Timezone,
+		},
+	}
+	for _, item := range f.List {
+		r.List = append(r.List, forecastItem{
+			Dt:         unix(item.Time),
+			Main:       item.Main,
+			Weather:    item.Conditions,
+			Clouds:     item.Clouds,
+			Wind:       item.Wind,
+			Visibility: item.Visibility,
+			DtTxt:      item.Time.UTC().Format("2006-01-02 15:04:05"),
+		})
+	}
+	return r
+}
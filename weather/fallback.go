@@ -0,0 +1,100 @@
+package weather
+
+import (
+	"context"
+	"errors"
+
+	"github.com/PicPay/go-test-workshop/internal/logging"
+)
+
+//Fallback é um UseCase que consulta os provedores em ordem, passando para o próximo quando o anterior
+//está fora do ar, recusou a chave, atingiu o limite de requisições ou não oferece a consulta.
+//Erros causados pela própria consulta, como coordenadas inválidas ou cidade não encontrada, são retornados
+//imediatamente, pois o próximo provedor falharia da mesma forma
+type Fallback struct {
+	providers []NamedProvider
+}
+
+//NamedProvider é um provedor da cadeia. Name aparece no log quando o provedor falha e deve ser o mesmo
+//usado nas métricas, como openweather ou openmeteo
+type NamedProvider struct {
+	Name    string
+	UseCase UseCase
+}
+
+//NewFallback cria a cadeia com o provedor principal seguido dos alternativos
+func NewFallback(primary NamedProvider, fallbacks ...NamedProvider) *Fallback {
+	return &Fallback{
+		providers: append([]NamedProvider{primary}, fallbacks...),
+	}
+}
+
+func (f *Fallback) Get(ctx context.Context, c Coord, opts Options) (*Weather, error) {
+	v, err := f.try(ctx, func(p UseCase) (interface{}, error) {
+		return p.Get(ctx, c, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Weather), nil
+}
+
+func (f *Fallback) ByCity(ctx context.Context, query string, opts Options) (*Weather, error) {
+	v, err := f.try(ctx, func(p UseCase) (interface{}, error) {
+		return p.ByCity(ctx, query, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Weather), nil
+}
+
+func (f *Fallback) ByZip(ctx context.Context, zip, country string, opts Options) (*Weather, error) {
+	v, err := f.try(ctx, func(p UseCase) (interface{}, error) {
+		return p.ByZip(ctx, zip, country, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Weather), nil
+}
+
+func (f *Fallback) Forecast(ctx context.Context, c Coord, opts Options) (*Forecast, error) {
+	v, err := f.try(ctx, func(p UseCase) (interface{}, error) {
+		return p.Forecast(ctx, c, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Forecast), nil
+}
+
+//try chama call com cada provedor até um deles responder. Se todos falharem retorna o erro do último
+func (f *Fallback) try(ctx context.Context, call func(UseCase) (interface{}, error)) (interface{}, error) {
+	var err error
+	for i, p := range f.providers {
+		var v interface{}
+		v, err = call(p.UseCase)
+		if err == nil {
+			return v, nil
+		}
+		if ctx.Err() != nil || !fallbackOn(err) {
+			return nil, err
+		}
+		if i < len(f.providers)-1 {
+			logging.FromContext(ctx).Warn("weather provider failed, trying the next one",
+				"provider", p.Name, "next", f.providers[i+1].Name, "error", err.Error())
+		}
+	}
+	return nil, err
+}
+
+//fallbackOn indica se o erro é do provedor, e não da consulta, e portanto vale a pena tentar o próximo
+func fallbackOn(err error) bool {
+	for _, target := range []error{ErrInvalidCoord, ErrInvalidQuery, ErrInvalidOptions, ErrBadRequest, ErrNotFound} {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package weather_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/internal/logging"
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/PicPay/go-test-workshop/weather/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+//newFallback cria a cadeia com primary como openweather e secondary como openmeteo
+func newFallback(primary, secondary weather.UseCase) *weather.Fallback {
+	return weather.NewFallback(
+		weather.NamedProvider{Name: "openweather", UseCase: primary},
+		weather.NamedProvider{Name: "openmeteo", UseCase: secondary},
+	)
+}
+
+//warnings é um logging.Logger que guarda os argumentos das chamadas a Warn
+type warnings [][]interface{}
+
+func (w *warnings) Info(msg string, args ...interface{})  {}
+func (w *warnings) Error(msg string, args ...interface{}) {}
+
+func (w *warnings) Warn(msg string, args ...interface{}) {
+	*w = append(*w, append([]interface{}{msg}, args...))
+}
+
+func TestFallback(t *testing.T) {
+	ctx := context.Background()
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+	opts := weather.Options{}
+	floripa := &weather.Weather{Name: "Florianópolis"}
+	t.Run("usa o principal quando ele responde", func(t *testing.T) {
+		primary := mocks.NewUseCase(t)
+		secondary := mocks.NewUseCase(t)
+		primary.On("Get", ctx, coord, opts).Return(floripa, nil).Once()
+		w, err := newFallback(primary, secondary).Get(ctx, coord, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, floripa, w)
+	})
+	providerErrors := []error{
+		&weather.UpstreamError{Kind: weather.ErrUnavailable, StatusCode: 503},
+		&weather.UpstreamError{Kind: weather.ErrRateLimited, StatusCode: 429},
+		&weather.UpstreamError{Kind: weather.ErrUnauthorized, StatusCode: 401},
+		&weather.UpstreamError{Kind: weather.ErrUnavailable, Err: weather.ErrCircuitOpen},
+		errors.New("invalid character '<' looking for beginning of value"),
+	}
+	for _, providerErr := range providerErrors {
+		t.Run(fmt.Sprintf("passa para o próximo com %s", providerErr), func(t *testing.T) {
+			primary := mocks.NewUseCase(t)
+			secondary := mocks.NewUseCase(t)
+			primary.On("Get", ctx, coord, opts).Return(nil, providerErr).Once()
+			secondary.On("Get", ctx, coord, opts).Return(floripa, nil).Once()
+			w, err := newFallback(primary, secondary).Get(ctx, coord, opts)
+			assert.Nil(t, err)
+			assert.Equal(t, floripa, w)
+		})
+	}
+	t.Run("registra o nome do provedor que falhou", func(t *testing.T) {
+		var logged warnings
+		ctx := logging.NewContext(ctx, &logged)
+		primary := mocks.NewUseCase(t)
+		secondary := mocks.NewUseCase(t)
+		primary.On("Get", ctx, coord, opts).Return(nil, &weather.UpstreamError{Kind: weather.ErrUnavailable, StatusCode: 503}).Once()
+		secondary.On("Get", ctx, coord, opts).Return(floripa, nil).Once()
+		_, err := newFallback(primary, secondary).Get(ctx, coord, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, warnings{{
+			"weather provider failed, trying the next one",
+			"provider", "openweather", "next", "openmeteo", "error", "weather api: unavailable: 503",
+		}}, logged)
+	})
+	t.Run("passa para o próximo quando a consulta não é suportada", func(t *testing.T) {
+		primary := mocks.NewUseCase(t)
+		secondary := mocks.NewUseCase(t)
+		primary.On("ByZip", ctx, "88010-000", "BR", opts).Return(nil, fmt.Errorf("%w: zip", weather.ErrUnsupported)).Once()
+		secondary.On("ByZip", ctx, "88010-000", "BR", opts).Return(floripa, nil).Once()
+		w, err := newFallback(primary, secondary).ByZip(ctx, "88010-000", "BR", opts)
+		assert.Nil(t, err)
+		assert.Equal(t, floripa, w)
+	})
+	t.Run("não passa para o próximo quando a consulta é inválida", func(t *testing.T) {
+		primary := mocks.NewUseCase(t)
+		secondary := mocks.NewUseCase(t)
+		primary.On("ByCity", ctx, "Atlantida", opts).
+			Return(nil, &weather.UpstreamError{Kind: weather.ErrNotFound, StatusCode: 404, Message: "city not found"}).
+			Once()
+		w, err := newFallback(primary, secondary).ByCity(ctx, "Atlantida", opts)
+		assert.Nil(t, w)
+		assert.ErrorIs(t, err, weather.ErrNotFound)
+	})
+	t.Run("retorna o erro do último quando todos falham", func(t *testing.T) {
+		primary := mocks.NewUseCase(t)
+		secondary := mocks.NewUseCase(t)
+		primary.On("Forecast", ctx, coord, opts).
+			Return(nil, &weather.UpstreamError{Kind: weather.ErrUnavailable, StatusCode: 500}).
+			Once()
+		secondary.On("Forecast", ctx, coord, opts).
+			Return(nil, &weather.UpstreamError{Kind: weather.ErrRateLimited, StatusCode: 429}).
+			Once()
+		f, err := newFallback(primary, secondary).Forecast(ctx, coord, opts)
+		assert.Nil(t, f)
+		assert.ErrorIs(t, err, weather.ErrRateLimited)
+	})
+	t.Run("não passa para o próximo com o contexto cancelado", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		primary := mocks.NewUseCase(t)
+		secondary := mocks.NewUseCase(t)
+		primary.On("Get", ctx, coord, opts).
+			Return(nil, &weather.UpstreamError{Kind: weather.ErrUnavailable, Err: context.Canceled}).
+			Once()
+		_, err := newFallback(primary, secondary).Get(ctx, coord, opts)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestStatic(t *testing.T) {
+	ctx := context.Background()
+	s := weather.NewStatic()
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+	w, err := s.Get(ctx, coord, weather.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, coord, w.Coord)
+	assert.Equal(t, weather.UnitsMetric, w.Units)
+	assert.False(t, w.Time.IsZero())
+
+	_, err = s.Get(ctx, weather.Coord{Lat: 91}, weather.Options{})
+	assert.ErrorIs(t, err, weather.ErrInvalidCoord)
+	_, err = s.ByCity(ctx, "", weather.Options{})
+	assert.ErrorIs(t, err, weather.ErrInvalidQuery)
+
+	fixed := weather.Weather{Name: "Teste", Units: weather.UnitsImperial}
+	s = weather.NewStatic(weather.WithStaticWeather(fixed))
+	w, err = s.ByZip(ctx, "88010-000", "BR", weather.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Teste", w.Name)
+	assert.Equal(t, weather.UnitsImperial, w.Units)
+}
@@ -0,0 +1,54 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+//getJSON faz um GET em u e decodifica a resposta em v. Respostas diferentes de 200, corpos que não podem ser
+//decodificados e falhas de conexão são retornadas como *UpstreamError
+func getJSON(ctx context.Context, client HTTPClient, u string, v interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(request)
+	if err != nil {
+		return &UpstreamError{Kind: ErrUnavailable, Err: err}
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return &UpstreamError{Kind: ErrUnavailable, StatusCode: resp.StatusCode, Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return upstreamError(resp.StatusCode, body)
+	}
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		return &UpstreamError{Kind: ErrUnexpected, StatusCode: resp.StatusCode, Message: "invalid response body", Err: err}
+	}
+	return nil
+}
+
+//upstreamError monta o erro a partir de uma resposta diferente de 200. A OpenWeather retorna o motivo
+//no formato {"cod": 401, "message": "Invalid API key..."} e a Open-Meteo em {"error": true, "reason": "..."}
+func upstreamError(statusCode int, body []byte) error {
+	var apiErr struct {
+		Message string `json:"message"`
+		Reason  string `json:"reason"`
+	}
+	_ = json.Unmarshal(body, &apiErr)
+	msg := apiErr.Message
+	if msg == "" {
+		msg = apiErr.Reason
+	}
+	return &UpstreamError{
+		Kind:       classify(statusCode),
+		StatusCode: statusCode,
+		Message:    msg,
+	}
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	weather "github.com/PicPay/go-test-workshop/weather"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,82 @@ type UseCase struct {
 	mock.Mock
 }
 
-// Get provides a mock function with given fields: lat, long
-func (_m *UseCase) Get(lat string, long string) (*weather.Weather, error) {
-	ret := _m.Called(lat, long)
+// ByCity provides a mock function with given fields: ctx, query, opts
+func (_m *UseCase) ByCity(ctx context.Context, query string, opts weather.Options) (*weather.Weather, error) {
+	ret := _m.Called(ctx, query, opts)
+
+	var r0 *weather.Weather
+	if rf, ok := ret.Get(0).(func(context.Context, string, weather.Options) *weather.Weather); ok {
+		r0 = rf(ctx, query, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*weather.Weather)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, weather.Options) error); ok {
+		r1 = rf(ctx, query, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ByZip provides a mock function with given fields: ctx, zip, country, opts
+func (_m *UseCase) ByZip(ctx context.Context, zip string, country string, opts weather.Options) (*weather.Weather, error) {
+	ret := _m.Called(ctx, zip, country, opts)
+
+	var r0 *weather.Weather
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, weather.Options) *weather.Weather); ok {
+		r0 = rf(ctx, zip, country, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*weather.Weather)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, weather.Options) error); ok {
+		r1 = rf(ctx, zip, country, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Forecast provides a mock function with given fields: ctx, c, opts
+func (_m *UseCase) Forecast(ctx context.Context, c weather.Coord, opts weather.Options) (*weather.Forecast, error) {
+	ret := _m.Called(ctx, c, opts)
+
+	var r0 *weather.Forecast
+	if rf, ok := ret.Get(0).(func(context.Context, weather.Coord, weather.Options) *weather.Forecast); ok {
+		r0 = rf(ctx, c, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*weather.Forecast)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, weather.Coord, weather.Options) error); ok {
+		r1 = rf(ctx, c, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, c, opts
+func (_m *UseCase) Get(ctx context.Context, c weather.Coord, opts weather.Options) (*weather.Weather, error) {
+	ret := _m.Called(ctx, c, opts)
 
 	var r0 *weather.Weather
-	if rf, ok := ret.Get(0).(func(string, string) *weather.Weather); ok {
-		r0 = rf(lat, long)
+	if rf, ok := ret.Get(0).(func(context.Context, weather.Coord, weather.Options) *weather.Weather); ok {
+		r0 = rf(ctx, c, opts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*weather.Weather)
@@ -26,8 +97,8 @@ func (_m *UseCase) Get(lat string, long string) (*weather.Weather, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(lat, long)
+	if rf, ok := ret.Get(1).(func(context.Context, weather.Coord, weather.Options) error); ok {
+		r1 = rf(ctx, c, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,378 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//OpenMeteo é um UseCase que usa a API gratuita da Open-Meteo, que não precisa de chave.
+//A Open-Meteo não oferece busca por código postal, então ByZip retorna ErrUnsupported
+type OpenMeteo struct {
+	client       HTTPClient
+	url          string
+	geocodingURL string
+	defaults     Options
+}
+
+type OpenMeteoOption func(*OpenMeteo)
+
+//NewOpenMeteo cria o provedor Open-Meteo
+func NewOpenMeteo(options ...OpenMeteoOption) *OpenMeteo {
+	o := &OpenMeteo{
+		client:       &http.Client{Timeout: time.Duration(1) * time.Second},
+		url:          "https://api.open-meteo.com/v1",
+		geocodingURL: "https://geocoding-api.open-meteo.com/v1",
+		defaults: Options{
+			Units: DefaultUnits,
+			Lang:  DefaultLang,
+		},
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+//WithOpenMeteoClient substitui o cliente HTTP
+func WithOpenMeteoClient(client HTTPClient) OpenMeteoOption {
+	return func(o *OpenMeteo) {
+		o.client = client
+	}
+}
+
+//WithOpenMeteoDefaults define as unidades e o idioma usados quando a consulta não informa
+func WithOpenMeteoDefaults(defaults Options) OpenMeteoOption {
+	return func(o *OpenMeteo) {
+		o.defaults = defaults.withDefaults(o.defaults)
+	}
+}
+
+func (o *OpenMeteo) Get(ctx context.Context, c Coord, opts Options) (*Weather, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	opts, err = o.options(opts)
+	if err != nil {
+		return nil, err
+	}
+	params := openMeteoCoord(c)
+	params.Set("current", "temperature_2m,relative_humidity_2m,apparent_temperature,pressure_msl,cloud_cover,visibility,wind_speed_10m,wind_direction_10m,weather_code,is_day")
+	params.Set("daily", "temperature_2m_max,temperature_2m_min,sunrise,sunset")
+	params.Set("forecast_days", "1")
+	var r openMeteoResponse
+	err = o.call(ctx, params, opts, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r.weather(opts), nil
+}
+
+//ByCity usa a API de geocodificação da Open-Meteo para encontrar as coordenadas da cidade.
+//Se a consulta terminar com um código de país, como em "Florianópolis,BR", a busca é restrita ao país
+func (o *OpenMeteo) ByCity(ctx context.Context, query string, opts Options) (*Weather, error) {
+	query, err := CityQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	opts, err = o.options(opts)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(query, ",")
+	params := url.Values{
+		"name":     {strings.TrimSpace(parts[0])},
+		"count":    {"1"},
+		"language": {strings.ToLower(strings.SplitN(opts.Lang, "_", 2)[0])},
+		"format":   {"json"},
+	}
+	if country := strings.TrimSpace(parts[len(parts)-1]); len(parts) > 1 && len(country) == 2 {
+		params.Set("countryCode", strings.ToUpper(country))
+	}
+	var r struct {
+		Results []struct {
+			ID          int64   `json:"id"`
+			Name        string  `json:"name"`
+			Latitude    float64 `json:"latitude"`
+			Longitude   float64 `json:"longitude"`
+			CountryCode string  `json:"country_code"`
+		} `json:"results"`
+	}
+	err = getJSON(ctx, o.client, o.geocodingURL+"/search?"+params.Encode(), &r)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Results) == 0 {
+		return nil, &UpstreamError{Kind: ErrNotFound, StatusCode: http.StatusNotFound, Message: "city not found"}
+	}
+	city := r.Results[0]
+	w, err := o.Get(ctx, Coord{Lat: city.Latitude, Lon: city.Longitude}, opts)
+	if err != nil {
+		return nil, err
+	}
+	w.ID = city.ID
+	w.Name = city.Name
+	w.Sys.Country = city.CountryCode
+	return w, nil
+}
+
+func (o *OpenMeteo) ByZip(ctx context.Context, zip, country string, opts Options) (*Weather, error) {
+	return nil, fmt.Errorf("%w: open-meteo does not support zip code lookups", ErrUnsupported)
+}
+
+//Forecast retorna a previsão dos próximos 5 dias. A Open-Meteo retorna valores a cada hora, então
+//são usados apenas os horários múltiplos de 3 (em UTC), como na OpenWeather
+func (o *OpenMeteo) Forecast(ctx context.Context, c Coord, opts Options) (*Forecast, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	opts, err = o.options(opts)
+	if err != nil {
+		return nil, err
+	}
+	params := openMeteoCoord(c)
+	params.Set("hourly", "temperature_2m,relative_humidity_2m,apparent_temperature,pressure_msl,cloud_cover,visibility,wind_speed_10m,wind_direction_10m,weather_code,is_day")
+	params.Set("forecast_hours", "120")
+	var r openMeteoResponse
+	err = o.call(ctx, params, opts, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r.forecast(opts), nil
+}
+
+func (o *OpenMeteo) options(opts Options) (Options, error) {
+	err := opts.Validate()
+	if err != nil {
+		return Options{}, err
+	}
+	return opts.withDefaults(o.defaults), nil
+}
+
+//call chama o endpoint /forecast, que retorna tanto as condições atuais quanto a previsão.
+//A Open-Meteo não tem Kelvin, então a conversão é feita na resposta
+func (o *OpenMeteo) call(ctx context.Context, params url.Values, opts Options, v interface{}) error {
+	params.Set("timezone", "auto")
+	params.Set("timeformat", "unixtime")
+	switch opts.Units {
+	case UnitsImperial:
+		params.Set("temperature_unit", "fahrenheit")
+		params.Set("wind_speed_unit", "mph")
+	default:
+		params.Set("temperature_unit", "celsius")
+		params.Set("wind_speed_unit", "ms")
+	}
+	return getJSON(ctx, o.client, o.url+"/forecast?"+params.Encode(), v)
+}
+
+func openMeteoCoord(c Coord) url.Values {
+	return url.Values{
+		"latitude":  {strconv.FormatFloat(c.Lat, 'f', -1, 64)},
+		"longitude": {strconv.FormatFloat(c.Lon, 'f', -1, 64)},
+	}
+}
+
+//openMeteoResponse é o formato da resposta de /v1/forecast da Open-Meteo
+type openMeteoResponse struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	UTCOffsetSeconds int     `json:"utc_offset_seconds"`
+	Current          struct {
+		Time                int64   `json:"time"`
+		Temperature         float64 `json:"temperature_2m"`
+		Humidity            float64 `json:"relative_humidity_2m"`
+		ApparentTemperature float64 `json:"apparent_temperature"`
+		Pressure            float64 `json:"pressure_msl"`
+		CloudCover          float64 `json:"cloud_cover"`
+		Visibility          float64 `json:"visibility"`
+		WindSpeed           float64 `json:"wind_speed_10m"`
+		WindDirection       float64 `json:"wind_direction_10m"`
+		WeatherCode         int     `json:"weather_code"`
+		IsDay               int     `json:"is_day"`
+	} `json:"current"`
+	Daily struct {
+		TemperatureMax []float64 `json:"temperature_2m_max"`
+		TemperatureMin []float64 `json:"temperature_2m_min"`
+		Sunrise        []int64   `json:"sunrise"`
+		Sunset         []int64   `json:"sunset"`
+	} `json:"daily"`
+	Hourly struct {
+		Time                []int64   `json:"time"`
+		Temperature         []float64 `json:"temperature_2m"`
+		Humidity            []float64 `json:"relative_humidity_2m"`
+		ApparentTemperature []float64 `json:"apparent_temperature"`
+		Pressure            []float64 `json:"pressure_msl"`
+		CloudCover          []float64 `json:"cloud_cover"`
+		Visibility          []float64 `json:"visibility"`
+		WindSpeed           []float64 `json:"wind_speed_10m"`
+		WindDirection       []float64 `json:"wind_direction_10m"`
+		WeatherCode         []int     `json:"weather_code"`
+		IsDay               []int     `json:"is_day"`
+	} `json:"hourly"`
+}
+
+func (r openMeteoResponse) weather(opts Options) *Weather {
+	loc := time.FixedZone("", r.UTCOffsetSeconds)
+	cur := r.Current
+	temp := temperature(opts.Units)
+	w := &Weather{
+		Coord: Coord{Lat: r.Latitude, Lon: r.Longitude},
+		Conditions: []Condition{
+			wmoCondition(cur.WeatherCode, cur.IsDay == 1, opts.Lang),
+		},
+		Main: Main{
+			Temp:      temp(cur.Temperature),
+			FeelsLike: temp(cur.ApparentTemperature),
+			TempMin:   temp(first(r.Daily.TemperatureMin, cur.Temperature)),
+			TempMax:   temp(first(r.Daily.TemperatureMax, cur.Temperature)),
+			Pressure:  int64(math.Round(cur.Pressure)),
+			Humidity:  int64(math.Round(cur.Humidity)),
+		},
+		Wind: Wind{
+			Speed: cur.WindSpeed,
+			Deg:   int64(math.Round(cur.WindDirection)),
+		},
+		Clouds:     Clouds{All: int64(math.Round(cur.CloudCover))},
+		Visibility: int64(math.Round(cur.Visibility)),
+		Time:       unixIn(cur.Time, loc),
+		Timezone:   r.UTCOffsetSeconds,
+		Units:      opts.Units,
+	}
+	if len(r.Daily.Sunrise) > 0 && len(r.Daily.Sunset) > 0 {
+		w.Sys.Sunrise = unixIn(r.Daily.Sunrise[0], loc)
+		w.Sys.Sunset = unixIn(r.Daily.Sunset[0], loc)
+	}
+	return w
+}
+
+func (r openMeteoResponse) forecast(opts Options) *Forecast {
+	h := r.Hourly
+	temp := temperature(opts.Units)
+	loc := time.FixedZone("", r.UTCOffsetSeconds)
+	f := &Forecast{
+		City:     City{Coord: Coord{Lat: r.Latitude, Lon: r.Longitude}},
+		List:     []ForecastItem{},
+		Timezone: r.UTCOffsetSeconds,
+		Units:    opts.Units,
+	}
+	for i, dt := range h.Time {
+		if time.Unix(dt, 0).UTC().Hour()%3 != 0 || i >= len(h.Temperature) || i >= len(h.Humidity) || i >= len(h.ApparentTemperature) ||
+			i >= len(h.Pressure) || i >= len(h.WindSpeed) || i >= len(h.WindDirection) {
+			continue
+		}
+		item := ForecastItem{
+			Time:       unixIn(dt, loc),
+			Conditions: []Condition{},
+			Main: Main{
+				Temp:      temp(h.Temperature[i]),
+				FeelsLike: temp(h.ApparentTemperature[i]),
+				TempMin:   temp(h.Temperature[i]),
+				TempMax:   temp(h.Temperature[i]),
+				Pressure:  int64(math.Round(h.Pressure[i])),
+				Humidity:  int64(math.Round(h.Humidity[i])),
+			},
+			Wind: Wind{
+				Speed: h.WindSpeed[i],
+				Deg:   int64(math.Round(h.WindDirection[i])),
+			},
+		}
+		if i < len(h.WeatherCode) {
+			day := i >= len(h.IsDay) || h.IsDay[i] == 1
+			item.Conditions = append(item.Conditions, wmoCondition(h.WeatherCode[i], day, opts.Lang))
+		}
+		if i < len(h.CloudCover) {
+			item.Clouds.All = int64(math.Round(h.CloudCover[i]))
+		}
+		if i < len(h.Visibility) {
+			item.Visibility = int64(math.Round(h.Visibility[i]))
+		}
+		f.List = append(f.List, item)
+	}
+	return f
+}
+
+//temperature retorna a conversão a ser aplicada nas temperaturas. A Open-Meteo não tem Kelvin, então
+//para UnitsStandard a consulta é feita em Celsius e convertida
+func temperature(units Units) func(float64) float64 {
+	if units == UnitsStandard {
+		return func(c float64) float64 {
+			return math.Round((c+273.15)*100) / 100
+		}
+	}
+	return func(v float64) float64 { return v }
+}
+
+func first(values []float64, fallback float64) float64 {
+	if len(values) == 0 {
+		return fallback
+	}
+	return values[0]
+}
+
+//wmoCondition converte o código de tempo da Organização Meteorológica Mundial usado pela Open-Meteo
+//para uma Condition no formato da OpenWeather, incluindo o ícone equivalente
+func wmoCondition(code int, day bool, lang string) Condition {
+	d, ok := wmoCodes[code]
+	if !ok {
+		d = wmoCode{"Clouds", "03", "unknown", "desconhecido"}
+	}
+	suffix := "n"
+	if day {
+		suffix = "d"
+	}
+	description := d.en
+	if strings.HasPrefix(strings.ToLower(lang), "pt") {
+		description = d.pt
+	}
+	return Condition{
+		ID:          int64(code),
+		Main:        d.main,
+		Description: description,
+		Icon:        d.icon + suffix,
+	}
+}
+
+type wmoCode struct {
+	main string
+	icon string
+	en   string
+	pt   string
+}
+
+var wmoCodes = map[int]wmoCode{
+	0:  {"Clear", "01", "clear sky", "céu limpo"},
+	1:  {"Clear", "02", "mainly clear", "predominantemente limpo"},
+	2:  {"Clouds", "03", "partly cloudy", "parcialmente nublado"},
+	3:  {"Clouds", "04", "overcast", "nublado"},
+	45: {"Fog", "50", "fog", "névoa"},
+	48: {"Fog", "50", "depositing rime fog", "névoa com geada"},
+	51: {"Drizzle", "09", "light drizzle", "garoa leve"},
+	53: {"Drizzle", "09", "moderate drizzle", "garoa moderada"},
+	55: {"Drizzle", "09", "dense drizzle", "garoa intensa"},
+	56: {"Drizzle", "09", "light freezing drizzle", "garoa congelante leve"},
+	57: {"Drizzle", "09", "dense freezing drizzle", "garoa congelante intensa"},
+	61: {"Rain", "10", "slight rain", "chuva leve"},
+	63: {"Rain", "10", "moderate rain", "chuva moderada"},
+	65: {"Rain", "10", "heavy rain", "chuva forte"},
+	66: {"Rain", "13", "light freezing rain", "chuva congelante leve"},
+	67: {"Rain", "13", "heavy freezing rain", "chuva congelante forte"},
+	71: {"Snow", "13", "slight snow fall", "neve leve"},
+	73: {"Snow", "13", "moderate snow fall", "neve moderada"},
+	75: {"Snow", "13", "heavy snow fall", "neve forte"},
+	77: {"Snow", "13", "snow grains", "grãos de neve"},
+	80: {"Rain", "09", "slight rain showers", "pancadas de chuva leves"},
+	81: {"Rain", "09", "moderate rain showers", "pancadas de chuva moderadas"},
+	82: {"Rain", "09", "violent rain showers", "pancadas de chuva fortes"},
+	85: {"Snow", "13", "slight snow showers", "pancadas de neve leves"},
+	86: {"Snow", "13", "heavy snow showers", "pancadas de neve fortes"},
+	95: {"Thunderstorm", "11", "thunderstorm", "trovoadas"},
+	96: {"Thunderstorm", "11", "thunderstorm with slight hail", "trovoadas com granizo leve"},
+	99: {"Thunderstorm", "11", "thunderstorm with heavy hail", "trovoadas com granizo forte"},
+}
@@ -0,0 +1,223 @@
+package weather_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/PicPay/go-test-workshop/weather/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func fixture(t *testing.T, name string) io.ReadCloser {
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+//openMeteoRequest verifica o endpoint chamado e os parâmetros informados em params
+func openMeteoRequest(url string, params map[string]string) interface{} {
+	return mock.MatchedBy(func(r *http.Request) bool {
+		if !strings.HasPrefix(r.URL.String(), url+"?") {
+			return false
+		}
+		q := r.URL.Query()
+		for k, v := range params {
+			if q.Get(k) != v {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestOpenMeteoGet(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewHTTPClient(t)
+	client.On("Do", openMeteoRequest("https://api.open-meteo.com/v1/forecast", map[string]string{
+		"latitude":         "-27.5969",
+		"longitude":        "-48.5495",
+		"temperature_unit": "celsius",
+		"wind_speed_unit":  "ms",
+		"timeformat":       "unixtime",
+	})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: fixture(t, "openmeteo_current.json")}, nil).
+		Once()
+	o := weather.NewOpenMeteo(weather.WithOpenMeteoClient(client))
+	brt := time.FixedZone("", -3*60*60)
+	expected := &weather.Weather{
+		Coord: weather.Coord{Lat: -27.625, Lon: -48.5},
+		Conditions: []weather.Condition{
+			{ID: 95, Main: "Thunderstorm", Description: "trovoadas", Icon: "11d"},
+		},
+		Main: weather.Main{
+			Temp:      19.7,
+			FeelsLike: 20.2,
+			TempMin:   16,
+			TempMax:   21,
+			Pressure:  1013,
+			Humidity:  95,
+		},
+		Wind:       weather.Wind{Speed: 2.6, Deg: 90},
+		Clouds:     weather.Clouds{All: 75},
+		Visibility: 10000,
+		Time:       time.Date(2022, 6, 21, 15, 30, 0, 0, brt),
+		Sys: weather.Sys{
+			Sunrise: time.Date(2022, 6, 21, 7, 4, 10, 0, brt),
+			Sunset:  time.Date(2022, 6, 21, 17, 27, 44, 0, brt),
+		},
+		Timezone: -10800,
+		Units:    weather.UnitsMetric,
+	}
+	w, err := o.Get(ctx, weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, expected, w)
+}
+
+func TestOpenMeteoUnits(t *testing.T) {
+	ctx := context.Background()
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+	t.Run("imperial", func(t *testing.T) {
+		client := mocks.NewHTTPClient(t)
+		client.On("Do", openMeteoRequest("https://api.open-meteo.com/v1/forecast", map[string]string{
+			"temperature_unit": "fahrenheit",
+			"wind_speed_unit":  "mph",
+		})).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: fixture(t, "openmeteo_current.json")}, nil).
+			Once()
+		o := weather.NewOpenMeteo(weather.WithOpenMeteoClient(client))
+		w, err := o.Get(ctx, coord, weather.Options{Units: weather.UnitsImperial, Lang: "en"})
+		assert.Nil(t, err)
+		assert.Equal(t, weather.UnitsImperial, w.Units)
+		assert.Equal(t, "thunderstorm", w.Conditions[0].Description)
+	})
+	t.Run("standard converte para Kelvin", func(t *testing.T) {
+		client := mocks.NewHTTPClient(t)
+		client.On("Do", openMeteoRequest("https://api.open-meteo.com/v1/forecast", map[string]string{
+			"temperature_unit": "celsius",
+		})).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: fixture(t, "openmeteo_current.json")}, nil).
+			Once()
+		o := weather.NewOpenMeteo(
+			weather.WithOpenMeteoClient(client),
+			weather.WithOpenMeteoDefaults(weather.Options{Units: weather.UnitsStandard}),
+		)
+		w, err := o.Get(ctx, coord, weather.Options{})
+		assert.Nil(t, err)
+		assert.Equal(t, weather.UnitsStandard, w.Units)
+		assert.Equal(t, 292.85, w.Main.Temp)
+		assert.Equal(t, "trovoadas", w.Conditions[0].Description)
+	})
+}
+
+func TestOpenMeteoByCity(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewHTTPClient(t)
+	client.On("Do", openMeteoRequest("https://geocoding-api.open-meteo.com/v1/search", map[string]string{
+		"name":        "Florianópolis",
+		"countryCode": "BR",
+		"language":    "pt",
+	})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: fixture(t, "openmeteo_geocoding.json")}, nil).
+		Once()
+	client.On("Do", openMeteoRequest("https://api.open-meteo.com/v1/forecast", map[string]string{
+		"latitude":  "-27.59667",
+		"longitude": "-48.54917",
+	})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: fixture(t, "openmeteo_current.json")}, nil).
+		Once()
+	o := weather.NewOpenMeteo(weather.WithOpenMeteoClient(client))
+	w, err := o.ByCity(ctx, "Florianópolis,BR", weather.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3463237), w.ID)
+	assert.Equal(t, "Florianópolis", w.Name)
+	assert.Equal(t, "BR", w.Sys.Country)
+
+	t.Run("cidade não encontrada", func(t *testing.T) {
+		client := mocks.NewHTTPClient(t)
+		client.On("Do", mock.Anything).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"generationtime_ms":0.5}`))}, nil).
+			Once()
+		o := weather.NewOpenMeteo(weather.WithOpenMeteoClient(client))
+		w, err := o.ByCity(ctx, "Atlantida", weather.Options{})
+		assert.Nil(t, w)
+		assert.ErrorIs(t, err, weather.ErrNotFound)
+	})
+	t.Run("idioma sem região", func(t *testing.T) {
+		client := mocks.NewHTTPClient(t)
+		client.On("Do", openMeteoRequest("https://geocoding-api.open-meteo.com/v1/search", map[string]string{
+			"language": "en",
+		})).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{}`))}, nil).
+			Once()
+		o := weather.NewOpenMeteo(weather.WithOpenMeteoClient(client))
+		_, err := o.ByCity(ctx, "Atlantida", weather.Options{Lang: "EN"})
+		assert.ErrorIs(t, err, weather.ErrNotFound)
+	})
+}
+
+func TestOpenMeteoByZip(t *testing.T) {
+	o := weather.NewOpenMeteo(weather.WithOpenMeteoClient(mocks.NewHTTPClient(t)))
+	w, err := o.ByZip(context.Background(), "88010-000", "BR", weather.Options{})
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, weather.ErrUnsupported)
+}
+
+func TestOpenMeteoForecast(t *testing.T) {
+	client := mocks.NewHTTPClient(t)
+	client.On("Do", openMeteoRequest("https://api.open-meteo.com/v1/forecast", map[string]string{
+		"forecast_hours": "120",
+	})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: fixture(t, "openmeteo_forecast.json")}, nil).
+		Once()
+	o := weather.NewOpenMeteo(weather.WithOpenMeteoClient(client))
+	f, err := o.Forecast(context.Background(), weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{})
+	assert.Nil(t, err)
+	brt := time.FixedZone("", -3*60*60)
+	expected := &weather.Forecast{
+		City: weather.City{Coord: weather.Coord{Lat: -27.625, Lon: -48.5}},
+		List: []weather.ForecastItem{
+			{
+				Time:       time.Date(2022, 6, 21, 18, 0, 0, 0, brt),
+				Conditions: []weather.Condition{{ID: 61, Main: "Rain", Description: "chuva leve", Icon: "10n"}},
+				Main:       weather.Main{Temp: 18.7, FeelsLike: 19, TempMin: 18.7, TempMax: 18.7, Pressure: 1014, Humidity: 93},
+				Wind:       weather.Wind{Speed: 3.1, Deg: 57},
+				Clouds:     weather.Clouds{All: 100},
+				Visibility: 10000,
+			},
+			{
+				Time:       time.Date(2022, 6, 21, 21, 0, 0, 0, brt),
+				Conditions: []weather.Condition{{ID: 63, Main: "Rain", Description: "chuva moderada", Icon: "10n"}},
+				Main:       weather.Main{Temp: 17.9, FeelsLike: 18.2, TempMin: 17.9, TempMax: 17.9, Pressure: 1015, Humidity: 94},
+				Wind:       weather.Wind{Speed: 2.2, Deg: 31},
+				Clouds:     weather.Clouds{All: 100},
+				Visibility: 8000,
+			},
+		},
+		Timezone: -10800,
+		Units:    weather.UnitsMetric,
+	}
+	assert.Equal(t, expected, f)
+}
+
+func TestOpenMeteoUpstreamError(t *testing.T) {
+	client := mocks.NewHTTPClient(t)
+	client.On("Do", mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusBadRequest, Body: ioutil.NopCloser(strings.NewReader(`{"error":true,"reason":"Latitude must be in range of -90 to 90°."}`))}, nil).
+		Once()
+	o := weather.NewOpenMeteo(weather.WithOpenMeteoClient(client))
+	_, err := o.Get(context.Background(), weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{})
+	assert.ErrorIs(t, err, weather.ErrBadRequest)
+	var ue *weather.UpstreamError
+	assert.ErrorAs(t, err, &ue)
+	assert.Equal(t, "Latitude must be in range of -90 to 90°.", ue.Message)
+}
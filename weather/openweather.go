@@ -0,0 +1,96 @@
+package weather
+
+import "time"
+
+//currentResponse é o formato da resposta de /data/2.5/weather da OpenWeather.
+//Os horários são enviados como unix timestamps em UTC e o fuso horário como segundos em relação ao UTC
+type currentResponse struct {
+	ID         int64       `json:"id"`
+	Name       string      `json:"name"`
+	Coord      Coord       `json:"coord"`
+	Weather    []Condition `json:"weather"`
+	Main       Main        `json:"main"`
+	Wind       Wind        `json:"wind"`
+	Clouds     Clouds      `json:"clouds"`
+	Visibility int64       `json:"visibility"`
+	Dt         int64       `json:"dt"`
+	Sys        struct {
+		Country string `json:"country"`
+		Sunrise int64  `json:"sunrise"`
+		Sunset  int64  `json:"sunset"`
+	} `json:"sys"`
+	Timezone int `json:"timezone"`
+}
+
+//weather converte a resposta para o modelo Weather, aplicando o fuso horário da localização aos horários
+func (r currentResponse) weather() *Weather {
+	loc := time.FixedZone("", r.Timezone)
+	return &Weather{
+		ID:         r.ID,
+		Name:       r.Name,
+		Coord:      r.Coord,
+		Conditions: r.Weather,
+		Main:       r.Main,
+		Wind:       r.Wind,
+		Clouds:     r.Clouds,
+		Visibility: r.Visibility,
+		Time:       unixIn(r.Dt, loc),
+		Sys: Sys{
+			Country: r.Sys.Country,
+			Sunrise: unixIn(r.Sys.Sunrise, loc),
+			Sunset:  unixIn(r.Sys.Sunset, loc),
+		},
+		Timezone: r.Timezone,
+	}
+}
+
+//forecastResponse é o formato da resposta de /data/2.5/forecast da OpenWeather
+type forecastResponse struct {
+	List []struct {
+		Dt         int64       `json:"dt"`
+		Weather    []Condition `json:"weather"`
+		Main       Main        `json:"main"`
+		Wind       Wind        `json:"wind"`
+		Clouds     Clouds      `json:"clouds"`
+		Visibility int64       `json:"visibility"`
+	} `json:"list"`
+	City struct {
+		Name     string `json:"name"`
+		Country  string `json:"country"`
+		Coord    Coord  `json:"coord"`
+		Timezone int    `json:"timezone"`
+	} `json:"city"`
+}
+
+//forecast converte a resposta para o modelo Forecast, com os horários no fuso horário da localização, como em weather
+func (r forecastResponse) forecast() *Forecast {
+	loc := time.FixedZone("", r.City.Timezone)
+	f := &Forecast{
+		City: City{
+			Name:    r.City.Name,
+			Country: r.City.Country,
+			Coord:   r.City.Coord,
+		},
+		List:     make([]ForecastItem, 0, len(r.List)),
+		Timezone: r.City.Timezone,
+	}
+	for _, item := range r.List {
+		f.List = append(f.List, ForecastItem{
+			Time:       unixIn(item.Dt, loc),
+			Conditions: item.Weather,
+			Main:       item.Main,
+			Wind:       item.Wind,
+			Clouds:     item.Clouds,
+			Visibility: item.Visibility,
+		})
+	}
+	return f
+}
+
+//unixIn converte o timestamp para o fuso horário loc. Zero indica que o campo não foi enviado e resulta em time.Time{}
+func unixIn(sec int64, loc *time.Location) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).In(loc)
+}
@@ -0,0 +1,62 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+//ErrInvalidOptions é retornado quando as unidades ou o idioma informados não são suportados
+var ErrInvalidOptions = errors.New("invalid weather options")
+
+//Units sistema de unidades das temperaturas e da velocidade do vento
+type Units string
+
+const (
+	//UnitsMetric temperaturas em Celsius e vento em metros por segundo
+	UnitsMetric Units = "metric"
+	//UnitsImperial temperaturas em Fahrenheit e vento em milhas por hora
+	UnitsImperial Units = "imperial"
+	//UnitsStandard temperaturas em Kelvin e vento em metros por segundo
+	UnitsStandard Units = "standard"
+)
+
+const (
+	//DefaultUnits unidades usadas quando não informadas
+	DefaultUnits = UnitsMetric
+	//DefaultLang idioma das descrições quando não informado
+	DefaultLang = "pt_br"
+)
+
+var langPattern = regexp.MustCompile(`^[a-zA-Z]{2}(_[a-zA-Z]{2})?$`)
+
+//Options permite escolher, em cada consulta, as unidades e o idioma das descrições.
+//Os campos vazios usam os valores padrão do Service
+type Options struct {
+	Units Units
+	Lang  string
+}
+
+//Validate verifica se as unidades e o idioma são suportados
+func (o Options) Validate() error {
+	switch o.Units {
+	case "", UnitsMetric, UnitsImperial, UnitsStandard:
+	default:
+		return fmt.Errorf("%w: units must be metric, imperial or standard", ErrInvalidOptions)
+	}
+	if o.Lang != "" && !langPattern.MatchString(o.Lang) {
+		return fmt.Errorf("%w: lang must be a language code like en or pt_br", ErrInvalidOptions)
+	}
+	return nil
+}
+
+//withDefaults preenche os campos vazios com os valores de defaults
+func (o Options) withDefaults(defaults Options) Options {
+	if o.Units == "" {
+		o.Units = defaults.Units
+	}
+	if o.Lang == "" {
+		o.Lang = defaults.Lang
+	}
+	return o
+}
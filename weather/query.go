@@ -0,0 +1,63 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//ErrInvalidQuery é retornado quando a consulta por cidade ou código postal está vazia ou mal formada
+var ErrInvalidQuery = errors.New("invalid location query")
+
+//maxCityQuery tamanho máximo, em caracteres, da consulta por cidade
+const maxCityQuery = 100
+
+//CityQuery valida e normaliza a consulta por cidade, no formato "cidade", "cidade,país" ou "cidade,estado,país"
+func CityQuery(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", fmt.Errorf("%w: city is required", ErrInvalidQuery)
+	}
+	if utf8.RuneCountInString(query) > maxCityQuery {
+		return "", fmt.Errorf("%w: city must have at most %d characters", ErrInvalidQuery, maxCityQuery)
+	}
+	for _, r := range query {
+		if unicode.IsControl(r) {
+			return "", fmt.Errorf("%w: city contains invalid characters", ErrInvalidQuery)
+		}
+	}
+	return query, nil
+}
+
+//ZipQuery valida o código postal e o país e retorna a consulta no formato "código,país".
+//O código pode conter letras, números, espaços e hífen, como em 88010-000 ou SW1A 1AA
+func ZipQuery(zip, country string) (string, error) {
+	zip = strings.TrimSpace(zip)
+	if len(zip) < 3 || len(zip) > 10 {
+		return "", fmt.Errorf("%w: zip must have between 3 and 10 characters", ErrInvalidQuery)
+	}
+	for _, r := range zip {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == ' ') {
+			return "", fmt.Errorf("%w: zip contains invalid characters", ErrInvalidQuery)
+		}
+	}
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		return zip, nil
+	}
+	if len(country) != 2 || !isASCIILetters(country) {
+		return "", fmt.Errorf("%w: country must be an ISO 3166 code like BR", ErrInvalidQuery)
+	}
+	return zip + "," + country, nil
+}
+
+func isASCIILetters(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package weather_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCityQuery(t *testing.T) {
+	q, err := weather.CityQuery("  São José,SC,BR ")
+	assert.Nil(t, err)
+	assert.Equal(t, "São José,SC,BR", q)
+
+	for name, query := range map[string]string{
+		"vazia":              "",
+		"somente espaços":    "   ",
+		"muito longa":        strings.Repeat("a", 101),
+		"caractere especial": "Florian\x00ópolis",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := weather.CityQuery(query)
+			assert.ErrorIs(t, err, weather.ErrInvalidQuery)
+		})
+	}
+}
+
+func TestZipQuery(t *testing.T) {
+	t.Run("com país", func(t *testing.T) {
+		q, err := weather.ZipQuery("88010-000", "br")
+		assert.Nil(t, err)
+		assert.Equal(t, "88010-000,BR", q)
+	})
+	t.Run("sem país", func(t *testing.T) {
+		q, err := weather.ZipQuery("94040", "")
+		assert.Nil(t, err)
+		assert.Equal(t, "94040", q)
+	})
+	invalid := []struct {
+		name    string
+		zip     string
+		country string
+	}{
+		{"vazio", "", "BR"},
+		{"muito longo", "88010-000-000", "BR"},
+		{"caracteres inválidos", "88010&appid=x", "BR"},
+		{"país inválido", "88010-000", "BRA"},
+		{"país com números", "88010-000", "B1"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := weather.ZipQuery(tt.zip, tt.country)
+			assert.ErrorIs(t, err, weather.ErrInvalidQuery)
+		})
+	}
+}
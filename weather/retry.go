@@ -0,0 +1,183 @@
+package weather
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	//DefaultMaxRetries quantidade de novas tentativas depois da primeira chamada
+	DefaultMaxRetries = 2
+	//DefaultBaseDelay espera antes da primeira nova tentativa. As seguintes dobram de tamanho
+	DefaultBaseDelay = 100 * time.Millisecond
+	//DefaultMaxDelay maior espera entre duas tentativas
+	DefaultMaxDelay = 2 * time.Second
+)
+
+//RetryClient é um HTTPClient que repete as chamadas que falharam por timeout, erro de conexão, 429 ou 5xx.
+//A espera entre as tentativas cresce exponencialmente, com jitter, e respeita o cabeçalho Retry-After
+type RetryClient struct {
+	next       HTTPClient
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	jitter     func(time.Duration) time.Duration
+	sleep      func(context.Context, time.Duration) error
+}
+
+type RetryOption func(*RetryClient)
+
+//NewRetryClient cria um RetryClient em volta de next
+func NewRetryClient(next HTTPClient, options ...RetryOption) *RetryClient {
+	c := &RetryClient{
+		next:       next,
+		maxRetries: DefaultMaxRetries,
+		baseDelay:  DefaultBaseDelay,
+		maxDelay:   DefaultMaxDelay,
+		jitter:     equalJitter,
+		sleep:      sleep,
+	}
+	for _, o := range options {
+		o(c)
+	}
+	return c
+}
+
+//WithMaxRetries define quantas vezes uma chamada pode ser repetida
+func WithMaxRetries(n int) RetryOption {
+	return func(c *RetryClient) {
+		c.maxRetries = n
+	}
+}
+
+//WithBackoff define a espera da primeira nova tentativa e a maior espera permitida
+func WithBackoff(base, max time.Duration) RetryOption {
+	return func(c *RetryClient) {
+		c.baseDelay = base
+		c.maxDelay = max
+	}
+}
+
+//WithSleep substitui a função usada para esperar entre as tentativas. Útil nos testes
+func WithSleep(f func(context.Context, time.Duration) error) RetryOption {
+	return func(c *RetryClient) {
+		c.sleep = f
+	}
+}
+
+//WithJitter substitui a função que aplica a variação aleatória na espera. Útil nos testes
+func WithJitter(f func(time.Duration) time.Duration) RetryOption {
+	return func(c *RetryClient) {
+		c.jitter = f
+	}
+}
+
+func (c *RetryClient) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		resp, err := c.next.Do(req)
+		if attempt >= c.maxRetries || !retryable(resp, err) || ctx.Err() != nil || !rewindable(req) {
+			return resp, err
+		}
+		delay, ok := c.delay(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		err = c.sleep(ctx, delay)
+		if err != nil {
+			return nil, err
+		}
+		req, err = rewind(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+//delay calcula a espera antes da próxima tentativa. Se a API pediu, via Retry-After, uma espera maior
+//que a máxima configurada a chamada não é repetida
+func (c *RetryClient) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return d, d <= c.maxDelay
+		}
+	}
+	d := c.baseDelay << attempt
+	if d > c.maxDelay || d <= 0 {
+		d = c.maxDelay
+	}
+	return c.jitter(d), true
+}
+
+//retryable indica se vale a pena repetir a chamada
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+//rewindable indica se o corpo da requisição pode ser enviado novamente
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+//retryAfter lê o cabeçalho Retry-After, que pode conter segundos ou uma data
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+//equalJitter retorna um valor entre d/2 e d, para que clientes diferentes não repitam as chamadas ao mesmo tempo
+func equalJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
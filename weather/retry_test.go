@@ -0,0 +1,118 @@
+package weather_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/PicPay/go-test-workshop/weather/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func response(status int, header http.Header) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+	}
+}
+
+//retryClient cria um RetryClient que registra as esperas ao invés de dormir
+func retryClient(next weather.HTTPClient, delays *[]time.Duration, options ...weather.RetryOption) *weather.RetryClient {
+	options = append([]weather.RetryOption{
+		weather.WithBackoff(100*time.Millisecond, time.Second),
+		weather.WithJitter(func(d time.Duration) time.Duration { return d }),
+		weather.WithSleep(func(_ context.Context, d time.Duration) error {
+			*delays = append(*delays, d)
+			return nil
+		}),
+	}, options...)
+	return weather.NewRetryClient(next, options...)
+}
+
+func TestRetryClient(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.openweathermap.org/data/2.5/weather", nil)
+	t.Run("repete erros 5xx com espera exponencial", func(t *testing.T) {
+		var delays []time.Duration
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(response(http.StatusServiceUnavailable, nil), nil).Once()
+		next.On("Do", mock.Anything).Return(response(http.StatusBadGateway, nil), nil).Once()
+		next.On("Do", mock.Anything).Return(response(http.StatusOK, nil), nil).Once()
+		c := retryClient(next, &delays)
+		resp, err := c.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, delays)
+	})
+	t.Run("repete timeouts", func(t *testing.T) {
+		var delays []time.Duration
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(nil, errors.New("Client.Timeout exceeded while awaiting headers")).Once()
+		next.On("Do", mock.Anything).Return(response(http.StatusOK, nil), nil).Once()
+		c := retryClient(next, &delays)
+		resp, err := c.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, delays, 1)
+	})
+	t.Run("desiste depois do limite de tentativas", func(t *testing.T) {
+		var delays []time.Duration
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(response(http.StatusInternalServerError, nil), nil).Times(3)
+		c := retryClient(next, &delays, weather.WithMaxRetries(2))
+		resp, err := c.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Len(t, delays, 2)
+	})
+	t.Run("não repete erros 4xx", func(t *testing.T) {
+		var delays []time.Duration
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(response(http.StatusUnauthorized, nil), nil).Once()
+		c := retryClient(next, &delays)
+		resp, err := c.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Empty(t, delays)
+	})
+	t.Run("respeita o Retry-After", func(t *testing.T) {
+		var delays []time.Duration
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(response(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}}), nil).Once()
+		next.On("Do", mock.Anything).Return(response(http.StatusOK, nil), nil).Once()
+		c := retryClient(next, &delays)
+		resp, err := c.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []time.Duration{time.Second}, delays)
+	})
+	t.Run("não espera mais que o máximo pedido no Retry-After", func(t *testing.T) {
+		var delays []time.Duration
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(response(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"3600"}}), nil).Once()
+		c := retryClient(next, &delays)
+		resp, err := c.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Empty(t, delays)
+	})
+	t.Run("contexto cancelado durante a espera", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req := req.WithContext(ctx)
+		next := mocks.NewHTTPClient(t)
+		next.On("Do", mock.Anything).Return(response(http.StatusServiceUnavailable, nil), nil).Once()
+		c := weather.NewRetryClient(next, weather.WithSleep(func(ctx context.Context, _ time.Duration) error {
+			cancel()
+			return ctx.Err()
+		}))
+		resp, err := c.Do(req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -1,17 +1,19 @@
 package weather
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"context"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Service struct {
-	client HTTPClient
-	apiKey string
-	url    string
+	client   HTTPClient
+	apiKey   string
+	url      string
+	defaults Options
 }
 
 type ServiceOption func(*Service)
@@ -20,7 +22,11 @@ func NewService(apiKey string, options ...ServiceOption) *Service {
 	s := &Service{
 		client: &http.Client{Timeout: time.Duration(1) * time.Second},
 		apiKey: apiKey,
-		url:    "https://api.openweathermap.org/data/2.5/weather?units=metric&lang=pt_br",
+		url:    "https://api.openweathermap.org/data/2.5",
+		defaults: Options{
+			Units: DefaultUnits,
+			Lang:  DefaultLang,
+		},
 	}
 
 	for _, o := range options {
@@ -35,26 +41,128 @@ func WithClient(client HTTPClient) ServiceOption {
 	}
 }
 
-func (s *Service) Get(lat, long string) (*Weather, error) {
-	url := fmt.Sprintf("%s&lat=%s&lon=%s&appid=%s", s.url, lat, long, s.apiKey)
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+//WithBaseURL define o endereço da API, por exemplo o de um servidor do pacote weather/fake
+func WithBaseURL(url string) ServiceOption {
+	return func(s *Service) {
+		s.url = strings.TrimSuffix(url, "/")
+	}
+}
+
+//WithUnits define as unidades usadas quando a consulta não informa
+func WithUnits(units Units) ServiceOption {
+	return func(s *Service) {
+		s.defaults.Units = units
+	}
+}
+
+//WithLang define o idioma das descrições usado quando a consulta não informa
+func WithLang(lang string) ServiceOption {
+	return func(s *Service) {
+		s.defaults.Lang = lang
+	}
+}
+
+func (s *Service) Get(ctx context.Context, c Coord, opts Options) (*Weather, error) {
+	err := c.Validate()
 	if err != nil {
 		return nil, err
 	}
+	return s.current(ctx, coordParams(c), opts)
+}
+
+//ByCity retorna as condições atuais da cidade. A consulta segue o formato da OpenWeather: "cidade,país", por exemplo "Florianópolis,BR"
+func (s *Service) ByCity(ctx context.Context, query string, opts Options) (*Weather, error) {
+	query, err := CityQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.current(ctx, url.Values{"q": {query}}, opts)
+}
 
-	resp, err := s.client.Do(request)
+//ByZip retorna as condições atuais do código postal. country é o código ISO 3166 do país, como BR. Se vazio a OpenWeather considera US
+func (s *Service) ByZip(ctx context.Context, zip, country string, opts Options) (*Weather, error) {
+	query, err := ZipQuery(zip, country)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	return s.current(ctx, url.Values{"zip": {query}}, opts)
+}
+
+//current busca as condições atuais. A resposta da OpenWeather é convertida para o modelo Weather
+func (s *Service) current(ctx context.Context, params url.Values, opts Options) (*Weather, error) {
+	opts, err := s.options(opts)
 	if err != nil {
 		return nil, err
 	}
-	var w Weather
-	err = json.Unmarshal(body, &w)
+	var r currentResponse
+	err = s.call(ctx, "/weather", params, opts, &r)
 	if err != nil {
 		return nil, err
 	}
-	return &w, nil
+	w := r.weather()
+	w.Units = opts.Units
+	return w, nil
+}
+
+//Forecast retorna a previsão para os próximos 5 dias, em intervalos de 3 horas
+func (s *Service) Forecast(ctx context.Context, c Coord, opts Options) (*Forecast, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	opts, err = s.options(opts)
+	if err != nil {
+		return nil, err
+	}
+	var r forecastResponse
+	err = s.call(ctx, "/forecast", coordParams(c), opts, &r)
+	if err != nil {
+		return nil, err
+	}
+	f := r.forecast()
+	f.Units = opts.Units
+	return f, nil
+}
+
+//options valida as opções da consulta e completa com os valores padrão
+func (s *Service) options(opts Options) (Options, error) {
+	err := opts.Validate()
+	if err != nil {
+		return Options{}, err
+	}
+	return opts.withDefaults(s.defaults), nil
+}
+
+//call faz a chamada ao endpoint path da API com os parâmetros params e decodifica a resposta em v
+func (s *Service) call(ctx context.Context, path string, params url.Values, opts Options, v interface{}) error {
+	u, err := s.requestURL(path, params, opts)
+	if err != nil {
+		return err
+	}
+	return getJSON(ctx, s.client, u, v)
+}
+
+//requestURL adiciona a chave, as unidades e o idioma aos parâmetros do endpoint. Os valores são
+//codificados com url.Values, então não é possível injetar outros parâmetros na chamada
+func (s *Service) requestURL(path string, params url.Values, opts Options) (string, error) {
+	u, err := url.Parse(s.url + path)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	for k, v := range params {
+		q[k] = v
+	}
+	q.Set("units", string(opts.Units))
+	q.Set("lang", opts.Lang)
+	q.Set("appid", s.apiKey)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func coordParams(c Coord) url.Values {
+	return url.Values{
+		"lat": {strconv.FormatFloat(c.Lat, 'f', -1, 64)},
+		"lon": {strconv.FormatFloat(c.Lon, 'f', -1, 64)},
+	}
 }
@@ -1,39 +1,41 @@
 package weather_test
 
 import (
-	"bytes"
-	"fmt"
+	"context"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/PicPay/go-test-workshop/weather"
+	"github.com/PicPay/go-test-workshop/weather/fake"
 	"github.com/PicPay/go-test-workshop/weather/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGet(t *testing.T) {
-	client := mocks.NewHTTPClient(t)
-	lat := "-48.5495"
-	long := "-27.5969"
-	url := "https://api.openweathermap.org/data/2.5/weather?units=metric&lang=pt_br"
-	apiKey := "fake"
-
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s&lat=%s&lon=%s&appid=%s", url, lat, long, apiKey), nil)
-	assert.Nil(t, err)
-	json := `{"coord":{"lon":-48.5495,"lat":-27.5969},"weather":[{"id":211,"main":"Thunderstorm","description":"trovoadas","icon":"11d"}],"base":"stations","main":{"temp":19.69,"feels_like":20.2,"temp_min":15.99,"temp_max":20.96,"pressure":1013,"humidity":95},"visibility":10000,"wind":{"speed":2.57,"deg":90},"clouds":{"all":75},"dt":1655836456,"sys":{"type":2,"id":2018322,"country":"BR","sunrise":1655805850,"sunset":1655843264},"timezone":-10800,"id":3463237,"name":"Florianópolis","cod":200}`
-	body := ioutil.NopCloser(bytes.NewReader([]byte(json)))
-	client.On("Do", request).
-		Return(&http.Response{StatusCode: http.StatusOK, Body: body}, nil).
-		Once()
-	s := weather.NewService(apiKey,
-		weather.WithClient(client),
-	)
+	ctx := context.Background()
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+	brt := time.FixedZone("", -3*60*60)
 	expected := &weather.Weather{
+		ID:   3463237,
+		Name: "Florianópolis",
 		Coord: weather.Coord{
 			Lon: -48.5495,
 			Lat: -27.5969,
 		},
+		Conditions: []weather.Condition{
+			{
+				ID:          211,
+				Main:        "Thunderstorm",
+				Description: "trovoadas",
+				Icon:        "11d",
+			},
+		},
 		Main: weather.Main{
 			Temp:      19.69,
 			FeelsLike: 20.2,
@@ -46,9 +48,308 @@ func TestGet(t *testing.T) {
 			Speed: 2.57,
 			Deg:   90,
 		},
-		Name: "Florianópolis",
+		Clouds:     weather.Clouds{All: 75},
+		Visibility: 10000,
+		Time:       time.Date(2022, 6, 21, 15, 34, 16, 0, brt),
+		Sys: weather.Sys{
+			Country: "BR",
+			Sunrise: time.Date(2022, 6, 21, 7, 4, 10, 0, brt),
+			Sunset:  time.Date(2022, 6, 21, 17, 27, 44, 0, brt),
+		},
+		Timezone: -10800,
+		Units:    weather.UnitsMetric,
 	}
-	w, err := s.Get(lat, long)
+	server := fake.NewServer(fake.WithAPIKey("fake"))
+	defer server.Close()
+	server.SetWeather(coord, *expected)
+	s := weather.NewService("fake", weather.WithBaseURL(server.BaseURL()))
+
+	w, err := s.Get(ctx, coord, weather.Options{})
 	assert.Nil(t, err)
 	assert.Equal(t, expected, w)
+	assert.Equal(t, "2022-06-21T07:04:10-03:00", w.Sys.Sunrise.Format(time.RFC3339))
+	assert.Equal(t, []fake.Request{{
+		Path:  fake.BasePath + "/weather",
+		Query: url.Values{"appid": {"fake"}, "lang": {"pt_br"}, "lat": {"-27.5969"}, "lon": {"-48.5495"}, "units": {"metric"}},
+	}}, server.Requests())
+}
+
+func TestGetContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := weather.NewService("fake")
+	w, err := s.Get(ctx, weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{})
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGetUpstreamErrors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		kind       error
+		message    string
+	}{
+		{
+			name:       "chave inválida",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"cod":401, "message": "Invalid API key. Please see https://openweathermap.org/faq#error401 for more info."}`,
+			kind:       weather.ErrUnauthorized,
+			message:    "Invalid API key. Please see https://openweathermap.org/faq#error401 for more info.",
+		},
+		{
+			name:       "coordenadas inválidas",
+			statusCode: http.StatusBadRequest,
+			body:       `{"cod":"400","message":"wrong latitude"}`,
+			kind:       weather.ErrBadRequest,
+			message:    "wrong latitude",
+		},
+		{
+			name:       "localização não encontrada",
+			statusCode: http.StatusNotFound,
+			body:       `{"cod":"404","message":"city not found"}`,
+			kind:       weather.ErrNotFound,
+			message:    "city not found",
+		},
+		{
+			name:       "limite de requisições",
+			statusCode: http.StatusTooManyRequests,
+			body:       `{"cod":429,"message":"Your account is temporary blocked due to exceeding of requests limitation of your subscription type."}`,
+			kind:       weather.ErrRateLimited,
+			message:    "Your account is temporary blocked due to exceeding of requests limitation of your subscription type.",
+		},
+		{
+			name:       "erro no servidor sem corpo JSON",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>Bad Gateway</html>`,
+			kind:       weather.ErrUnavailable,
+		},
+		{
+			name:       "resposta 200 com corpo inválido",
+			statusCode: http.StatusOK,
+			body:       `<html>OK</html>`,
+			kind:       weather.ErrUnexpected,
+			message:    "invalid response body",
+		},
+		{
+			name:       "status inesperado",
+			statusCode: http.StatusMovedPermanently,
+			body:       ``,
+			kind:       weather.ErrUnexpected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := mocks.NewHTTPClient(t)
+			client.On("Do", mock.Anything).
+				Return(&http.Response{StatusCode: tt.statusCode, Body: ioutil.NopCloser(strings.NewReader(tt.body))}, nil).
+				Once()
+			s := weather.NewService("fake", weather.WithClient(client))
+			w, err := s.Get(ctx, weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{})
+			assert.Nil(t, w)
+			assert.ErrorIs(t, err, tt.kind)
+			var ue *weather.UpstreamError
+			assert.ErrorAs(t, err, &ue)
+			assert.Equal(t, tt.statusCode, ue.StatusCode)
+			assert.Equal(t, tt.message, ue.Message)
+		})
+	}
+	t.Run("falha de conexão", func(t *testing.T) {
+		client := mocks.NewHTTPClient(t)
+		client.On("Do", mock.Anything).
+			Return(nil, errors.New("connection refused")).
+			Once()
+		s := weather.NewService("fake", weather.WithClient(client))
+		w, err := s.Get(ctx, weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{})
+		assert.Nil(t, w)
+		assert.ErrorIs(t, err, weather.ErrUnavailable)
+	})
+}
+
+func TestGetInvalidCoord(t *testing.T) {
+	client := mocks.NewHTTPClient(t)
+	s := weather.NewService("fake", weather.WithClient(client))
+	w, err := s.Get(context.Background(), weather.Coord{Lat: 91, Lon: 0}, weather.Options{})
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, weather.ErrInvalidCoord)
+}
+
+func TestGetEscapesAPIKey(t *testing.T) {
+	client := mocks.NewHTTPClient(t)
+	client.On("Do", mock.MatchedBy(func(r *http.Request) bool {
+		q := r.URL.Query()
+		return q.Get("appid") == "fake&lat=0" && q.Get("lat") == "-27.5969" && len(q["lat"]) == 1
+	})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{}`))}, nil).
+		Once()
+	s := weather.NewService("fake&lat=0", weather.WithClient(client))
+	_, err := s.Get(context.Background(), weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{})
+	assert.Nil(t, err)
+}
+
+func TestForecast(t *testing.T) {
+	ctx := context.Background()
+	brt := time.FixedZone("", -3*60*60)
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+	expected := &weather.Forecast{
+		City: weather.City{
+			Name:    "Florianópolis",
+			Country: "BR",
+			Coord: weather.Coord{
+				Lat: -27.5969,
+				Lon: -48.5495,
+			},
+		},
+		List: []weather.ForecastItem{
+			{
+				Time:       time.Date(2022, 6, 21, 18, 0, 0, 0, brt),
+				Conditions: []weather.Condition{{ID: 500, Main: "Rain", Description: "chuva leve", Icon: "10n"}},
+				Main: weather.Main{
+					Temp:      18.74,
+					FeelsLike: 19.03,
+					TempMin:   18.74,
+					TempMax:   19.33,
+					Pressure:  1014,
+					Humidity:  93,
+				},
+				Wind: weather.Wind{
+					Speed: 3.09,
+					Deg:   57,
+				},
+				Clouds:     weather.Clouds{All: 100},
+				Visibility: 10000,
+			},
+			{
+				Time:       time.Date(2022, 6, 21, 21, 0, 0, 0, brt),
+				Conditions: []weather.Condition{{ID: 501, Main: "Rain", Description: "chuva moderada", Icon: "10n"}},
+				Main: weather.Main{
+					Temp:      17.96,
+					FeelsLike: 18.2,
+					TempMin:   17.44,
+					TempMax:   17.96,
+					Pressure:  1015,
+					Humidity:  94,
+				},
+				Wind: weather.Wind{
+					Speed: 2.2,
+					Deg:   31,
+				},
+				Clouds:     weather.Clouds{All: 100},
+				Visibility: 10000,
+			},
+		},
+		Timezone: -10800,
+		Units:    weather.UnitsMetric,
+	}
+	server := fake.NewServer(fake.WithAPIKey("fake"))
+	defer server.Close()
+	server.SetForecast(coord, *expected)
+	s := weather.NewService("fake", weather.WithBaseURL(server.BaseURL()))
+
+	f, err := s.Forecast(ctx, coord, weather.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, expected, f)
+	assert.Equal(t, []fake.Request{{
+		Path:  fake.BasePath + "/forecast",
+		Query: url.Values{"appid": {"fake"}, "lang": {"pt_br"}, "lat": {"-27.5969"}, "lon": {"-48.5495"}, "units": {"metric"}},
+	}}, server.Requests())
+}
+
+func TestForecastUpstreamError(t *testing.T) {
+	client := mocks.NewHTTPClient(t)
+	client.On("Do", mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusUnauthorized, Body: ioutil.NopCloser(strings.NewReader(`{"cod":401,"message":"Invalid API key"}`))}, nil).
+		Once()
+	s := weather.NewService("fake", weather.WithClient(client))
+	f, err := s.Forecast(context.Background(), weather.Coord{Lat: -27.5969, Lon: -48.5495}, weather.Options{})
+	assert.Nil(t, f)
+	assert.ErrorIs(t, err, weather.ErrUnauthorized)
+}
+
+func TestByCity(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewHTTPClient(t)
+	url := "https://api.openweathermap.org/data/2.5/weather?appid=fake&lang=pt_br&q=Florian%C3%B3polis%2CBR&units=metric"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	assert.Nil(t, err)
+	client.On("Do", request).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"coord":{"lon":-48.5495,"lat":-27.5969},"name":"Florianópolis"}`))}, nil).
+		Once()
+	s := weather.NewService("fake", weather.WithClient(client))
+	w, err := s.ByCity(ctx, " Florianópolis,BR ", weather.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, &weather.Weather{Coord: weather.Coord{Lat: -27.5969, Lon: -48.5495}, Name: "Florianópolis", Units: weather.UnitsMetric}, w)
+
+	t.Run("consulta vazia", func(t *testing.T) {
+		w, err := s.ByCity(ctx, " ", weather.Options{})
+		assert.Nil(t, w)
+		assert.ErrorIs(t, err, weather.ErrInvalidQuery)
+	})
+}
+
+func TestByZip(t *testing.T) {
+	ctx := context.Background()
+	client := mocks.NewHTTPClient(t)
+	url := "https://api.openweathermap.org/data/2.5/weather?appid=fake&lang=pt_br&units=metric&zip=88010-000%2CBR"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	assert.Nil(t, err)
+	client.On("Do", request).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"coord":{"lon":-48.5495,"lat":-27.5969},"name":"Florianópolis"}`))}, nil).
+		Once()
+	s := weather.NewService("fake", weather.WithClient(client))
+	w, err := s.ByZip(ctx, "88010-000", "br", weather.Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Florianópolis", w.Name)
+
+	t.Run("código postal não encontrado", func(t *testing.T) {
+		client := mocks.NewHTTPClient(t)
+		client.On("Do", mock.Anything).
+			Return(&http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(strings.NewReader(`{"cod":"404","message":"city not found"}`))}, nil).
+			Once()
+		s := weather.NewService("fake", weather.WithClient(client))
+		w, err := s.ByZip(ctx, "00000-000", "BR", weather.Options{})
+		assert.Nil(t, w)
+		assert.ErrorIs(t, err, weather.ErrNotFound)
+	})
+}
+
+func TestGetOptions(t *testing.T) {
+	ctx := context.Background()
+	coord := weather.Coord{Lat: -27.5969, Lon: -48.5495}
+	tests := []struct {
+		name     string
+		service  []weather.ServiceOption
+		opts     weather.Options
+		units    string
+		lang     string
+		expected weather.Units
+	}{
+		{"padrão", nil, weather.Options{}, "metric", "pt_br", weather.UnitsMetric},
+		{"padrão do serviço", []weather.ServiceOption{weather.WithUnits(weather.UnitsStandard), weather.WithLang("es")}, weather.Options{}, "standard", "es", weather.UnitsStandard},
+		{"informado na consulta", []weather.ServiceOption{weather.WithUnits(weather.UnitsStandard)}, weather.Options{Units: weather.UnitsImperial, Lang: "en"}, "imperial", "en", weather.UnitsImperial},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := mocks.NewHTTPClient(t)
+			client.On("Do", mock.MatchedBy(func(r *http.Request) bool {
+				q := r.URL.Query()
+				return q.Get("units") == tt.units && q.Get("lang") == tt.lang
+			})).
+				Return(&http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{}`))}, nil).
+				Once()
+			s := weather.NewService("fake", append(tt.service, weather.WithClient(client))...)
+			w, err := s.Get(ctx, coord, tt.opts)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, w.Units)
+		})
+	}
+	t.Run("opções inválidas", func(t *testing.T) {
+		s := weather.NewService("fake", weather.WithClient(mocks.NewHTTPClient(t)))
+		_, err := s.Get(ctx, coord, weather.Options{Units: "kelvin"})
+		assert.ErrorIs(t, err, weather.ErrInvalidOptions)
+		_, err = s.Forecast(ctx, coord, weather.Options{Lang: "pt-br&appid=x"})
+		assert.ErrorIs(t, err, weather.ErrInvalidOptions)
+	})
 }
@@ -0,0 +1,131 @@
+package weather
+
+import (
+	"context"
+	"time"
+)
+
+//Static é um UseCase que sempre retorna as mesmas condições, sem chamar nenhuma API. Pode ser usado para
+//executar a aplicação localmente sem chave ou como último provedor de um Fallback
+type Static struct {
+	weather  Weather
+	forecast Forecast
+}
+
+type StaticOption func(*Static)
+
+//NewStatic cria o provedor. Sem opções as respostas são condições típicas de Florianópolis em unidades métricas
+func NewStatic(options ...StaticOption) *Static {
+	s := &Static{
+		weather: Weather{
+			ID:   3463237,
+			Name: "Florianópolis",
+			Conditions: []Condition{
+				{ID: 802, Main: "Clouds", Description: "nuvens dispersas", Icon: "03d"},
+			},
+			Main: Main{
+				Temp:      22,
+				FeelsLike: 22,
+				TempMin:   19,
+				TempMax:   25,
+				Pressure:  1015,
+				Humidity:  75,
+			},
+			Wind:       Wind{Speed: 3, Deg: 90},
+			Clouds:     Clouds{All: 40},
+			Visibility: 10000,
+			Sys:        Sys{Country: "BR"},
+			Timezone:   -10800,
+			Units:      UnitsMetric,
+		},
+		forecast: Forecast{
+			City:  City{Name: "Florianópolis", Country: "BR"},
+			List:  []ForecastItem{},
+			Units: UnitsMetric,
+		},
+	}
+	for _, o := range options {
+		o(s)
+	}
+	return s
+}
+
+//WithStaticWeather define as condições retornadas por Get, ByCity e ByZip
+func WithStaticWeather(w Weather) StaticOption {
+	return func(s *Static) {
+		s.weather = w
+	}
+}
+
+//WithStaticForecast define a previsão retornada por Forecast
+func WithStaticForecast(f Forecast) StaticOption {
+	return func(s *Static) {
+		s.forecast = f
+	}
+}
+
+//Get retorna as condições fixas com as coordenadas consultadas. Os horários não definidos são preenchidos com o dia da consulta
+func (s *Static) Get(ctx context.Context, c Coord, opts Options) (*Weather, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.current(opts)
+	if err != nil {
+		return nil, err
+	}
+	w.Coord = c
+	return w, nil
+}
+
+func (s *Static) ByCity(ctx context.Context, query string, opts Options) (*Weather, error) {
+	_, err := CityQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.current(opts)
+}
+
+func (s *Static) ByZip(ctx context.Context, zip, country string, opts Options) (*Weather, error) {
+	_, err := ZipQuery(zip, country)
+	if err != nil {
+		return nil, err
+	}
+	return s.current(opts)
+}
+
+func (s *Static) Forecast(ctx context.Context, c Coord, opts Options) (*Forecast, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+	f := copyForecast(&s.forecast)
+	f.City.Coord = c
+	return f, nil
+}
+
+//current retorna uma cópia das condições. As unidades não são convertidas: a resposta informa em Units as unidades do valor fixo
+func (s *Static) current(opts Options) (*Weather, error) {
+	err := opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+	w := copyWeather(&s.weather)
+	loc := time.FixedZone("", w.Timezone)
+	now := time.Now().In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if w.Time.IsZero() {
+		w.Time = now.Truncate(time.Minute)
+	}
+	if w.Sys.Sunrise.IsZero() {
+		w.Sys.Sunrise = midnight.Add(6*time.Hour + 30*time.Minute)
+	}
+	if w.Sys.Sunset.IsZero() {
+		w.Sys.Sunset = midnight.Add(17*time.Hour + 30*time.Minute)
+	}
+	return w, nil
+}
@@ -1,14 +1,48 @@
 package weather
 
 import (
+	"context"
 	"net/http"
+	"time"
 )
 
+//Weather condições atuais de uma localização. Os horários estão no fuso horário da localização
 type Weather struct {
-	Coord Coord  `json:"coord"`
-	Main  Main   `json:"main"`
-	Wind  Wind   `json:"wind"`
-	Name  string `json:"name"`
+	ID         int64       `json:"id"`
+	Name       string      `json:"name"`
+	Coord      Coord       `json:"coord"`
+	Conditions []Condition `json:"conditions"`
+	Main       Main        `json:"main"`
+	Wind       Wind        `json:"wind"`
+	Clouds     Clouds      `json:"clouds"`
+	//Visibility em metros
+	Visibility int64 `json:"visibility"`
+	//Time é o momento em que as condições foram medidas
+	Time time.Time `json:"time"`
+	Sys  Sys       `json:"sys"`
+	//Timezone é a diferença, em segundos, em relação ao UTC
+	Timezone int `json:"timezone"`
+	//Units são as unidades das temperaturas e da velocidade do vento
+	Units Units `json:"units"`
+}
+
+//Condition descrição das condições, como "trovoadas", e o ícone correspondente
+type Condition struct {
+	ID          int64  `json:"id"`
+	Main        string `json:"main"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+}
+
+type Clouds struct {
+	//All é a porcentagem do céu coberta por nuvens
+	All int64 `json:"all"`
+}
+
+type Sys struct {
+	Country string    `json:"country"`
+	Sunrise time.Time `json:"sunrise"`
+	Sunset  time.Time `json:"sunset"`
 }
 
 type Coord struct {
@@ -30,10 +64,41 @@ type Wind struct {
 	Deg   int64   `json:"deg"`
 }
 
+//Forecast previsão para os próximos 5 dias, em intervalos de 3 horas. Os horários estão no fuso horário da localização
+type Forecast struct {
+	City City           `json:"city"`
+	List []ForecastItem `json:"list"`
+	//Timezone é a diferença, em segundos, em relação ao UTC
+	Timezone int `json:"timezone"`
+	//Units são as unidades das temperaturas e da velocidade do vento
+	Units Units `json:"units"`
+}
+
+type City struct {
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	Coord   Coord  `json:"coord"`
+}
+
+//ForecastItem previsão para um intervalo de 3 horas, com os mesmos campos das condições atuais em Weather
+type ForecastItem struct {
+	//Time é o início do intervalo
+	Time       time.Time   `json:"time"`
+	Conditions []Condition `json:"conditions"`
+	Main       Main        `json:"main"`
+	Wind       Wind        `json:"wind"`
+	Clouds     Clouds      `json:"clouds"`
+	//Visibility em metros
+	Visibility int64 `json:"visibility"`
+}
+
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
 type UseCase interface {
-	Get(lat, long string) (*Weather, error)
+	Get(ctx context.Context, c Coord, opts Options) (*Weather, error)
+	ByCity(ctx context.Context, query string, opts Options) (*Weather, error)
+	ByZip(ctx context.Context, zip, country string, opts Options) (*Weather, error)
+	Forecast(ctx context.Context, c Coord, opts Options) (*Forecast, error)
 }